@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+type generator struct {
+	fset    *token.FileSet
+	column  string
+	name    string
+	structs map[string]*ast.StructType
+	imports map[string]string
+	used    map[string]bool
+	consts  bytes.Buffer
+	decls   []string
+}
+
+// generate returns the source of the typed json paths of the struct typeName in the package of dir
+func generate(dir, typeName, column, name string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for pkgName, pkg := range pkgs {
+		g := &generator{
+			fset:    fset,
+			column:  column,
+			name:    name,
+			structs: map[string]*ast.StructType{},
+			imports: map[string]string{},
+			used:    map[string]bool{},
+		}
+		for _, file := range pkg.Files {
+			g.collect(file)
+		}
+
+		if root, ok := g.structs[typeName]; ok {
+			return g.generate(pkgName, typeName, root)
+		}
+	}
+	return nil, fmt.Errorf("struct %s not found in %s", typeName, dir)
+}
+
+// collect collects the structs and the imports of file
+func (g *generator) collect(file *ast.File) {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndexByte(path, '/')+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		g.imports[name] = spec.Path.Value
+		if spec.Name != nil {
+			g.imports[name] = spec.Name.Name + " " + spec.Path.Value
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if spec, ok := node.(*ast.TypeSpec); ok && spec.TypeParams == nil {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				g.structs[spec.Name.Name] = st
+			}
+		}
+		return true
+	})
+}
+
+func (g *generator) generate(pkgName, typeName string, root *ast.StructType) ([]byte, error) {
+	literal := g.structNode(lowerFirst(g.name), "", root, nil, nil, map[string]bool{typeName: true})
+
+	// standard packages are grouped before the others
+	std, others := []string{}, []string{strconv.Quote("gorm.io/datatypes")}
+	for name := range g.used {
+		if spec, ok := g.imports[name]; ok {
+			if path, _ := strconv.Unquote(spec[strings.IndexByte(spec, '"'):]); strings.Contains(strings.Split(path, "/")[0], ".") {
+				others = append(others, spec)
+			} else {
+				std = append(std, spec)
+			}
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by datatypes-gen. DO NOT EDIT.\n\npackage %s\n\n", pkgName)
+	fmt.Fprintf(&buf, "import (\n%s\n\n%s\n)\n\n", strings.Join(std, "\n"), strings.Join(others, "\n"))
+	fmt.Fprintf(&buf, "// Paths of %s in column %s\nconst (\n%s)\n\n", typeName, g.column, g.consts.String())
+	fmt.Fprintf(&buf, "// %s is the typed json paths of %s in column %s\nvar %s = %s\n", g.name, typeName, g.column, g.name, literal)
+	buf.WriteString(strings.Join(g.decls, ""))
+	return format.Source(buf.Bytes())
+}
+
+// structNode declares the struct named typeName of the paths of the fields of st, value is the type of the
+// struct itself, which is empty for the root, returns the literal of the declared struct
+func (g *generator) structNode(typeName, value string, st *ast.StructType, keys, names []string, parents map[string]bool) string {
+	// the struct is declared before the structs of its fields
+	idx := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var decl, literal bytes.Buffer
+	fmt.Fprintf(&decl, "\ntype %s struct {\n", typeName)
+	fmt.Fprintf(&literal, "%s{\n", typeName)
+	if value != "" {
+		fmt.Fprintf(&decl, "datatypes.JSONField[%s]\n", value)
+		fmt.Fprintf(&literal, "JSONField: %s,\n", g.field("JSONField", value, keys))
+	}
+
+	g.fields(st, keys, names, parents, func(name, fieldType, fieldLiteral string) {
+		fmt.Fprintf(&decl, "%s %s\n", name, fieldType)
+		fmt.Fprintf(&literal, "%s: %s,\n", name, fieldLiteral)
+	})
+
+	decl.WriteString("}\n")
+	literal.WriteString("}")
+	g.decls[idx] = decl.String()
+	return literal.String()
+}
+
+// fields walks the json fields of st, embedded structs are flattened like encoding/json
+func (g *generator) fields(st *ast.StructType, keys, names []string, parents map[string]bool, write func(name, fieldType, literal string)) {
+	for _, field := range st.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		key, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if key == "-" {
+			continue
+		}
+
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+
+		idents := field.Names
+		if len(idents) == 0 {
+			ident, _ := typ.(*ast.Ident)
+			if ident == nil {
+				continue
+			}
+			if embedded, ok := g.structs[ident.Name]; ok && key == "" && !parents[ident.Name] {
+				parents[ident.Name] = true
+				g.fields(embedded, keys, names, parents, write)
+				delete(parents, ident.Name)
+				continue
+			}
+			idents = []*ast.Ident{ident}
+		}
+
+		for _, ident := range idents {
+			if !ast.IsExported(ident.Name) {
+				continue
+			}
+
+			fieldKey := key
+			if fieldKey == "" {
+				fieldKey = ident.Name
+			}
+			fieldKeys := append(keys[:len(keys):len(keys)], fieldKey)
+			fieldNames := append(names[:len(names):len(names)], ident.Name)
+			fmt.Fprintf(&g.consts, "%s%sPath = %s\n", g.name, strings.Join(fieldNames, ""), strconv.Quote(datatypes.JSONKeysPath(fieldKeys...)))
+
+			name, fieldType, literal := ident.Name, "", ""
+			switch t := typ.(type) {
+			case *ast.StructType:
+				fieldType = lowerFirst(g.name) + strings.Join(fieldNames, "")
+				literal = g.structNode(fieldType, g.expr(t), t, fieldKeys, fieldNames, parents)
+			case *ast.Ident:
+				if nested, ok := g.structs[t.Name]; ok && !parents[t.Name] {
+					parents[t.Name] = true
+					fieldType = lowerFirst(g.name) + strings.Join(fieldNames, "")
+					literal = g.structNode(fieldType, t.Name, nested, fieldKeys, fieldNames, parents)
+					delete(parents, t.Name)
+				}
+			case *ast.ArrayType:
+				if elem, ok := t.Elt.(*ast.Ident); t.Len == nil && (!ok || elem.Name != "byte") {
+					fieldType = "datatypes.JSONSliceField[" + g.expr(t.Elt) + "]"
+					literal = g.field("JSONSliceField", g.expr(t.Elt), fieldKeys)
+				}
+			}
+			if fieldType == "" {
+				fieldType = "datatypes.JSONField[" + g.expr(typ) + "]"
+				literal = g.field("JSONField", g.expr(typ), fieldKeys)
+			}
+			write(name, fieldType, literal)
+		}
+	}
+}
+
+// field returns the constructor of the datatypes field kind of the json path keys
+func (g *generator) field(kind, value string, keys []string) string {
+	args := []string{strconv.Quote(g.column)}
+	for _, key := range keys {
+		args = append(args, strconv.Quote(key))
+	}
+	return fmt.Sprintf("datatypes.New%s[%s](%s)", kind, value, strings.Join(args, ", "))
+}
+
+// expr returns the source of the type expr, and records the imported packages it uses
+func (g *generator) expr(expr ast.Expr) string {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				g.used[ident.Name] = true
+			}
+		}
+		return true
+	})
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, g.fset, expr)
+	return buf.String()
+}
+
+func lowerFirst(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("testdata/models", "Attributes", "attributes", "UserAttrs")
+	if err != nil {
+		t.Fatalf("failed to generate, got error %v", err)
+	}
+
+	if err := typeCheck("testdata/models", "attributes_json_paths.go", src); err != nil {
+		t.Fatalf("failed to type check generated source, got error %v", err)
+	}
+
+	for _, expect := range []string{
+		"package models",
+		"\t\"time\"\n\n\t\"gorm.io/datatypes\"\n",
+		"UserAttrsOrgsOrgaPath   = \"orgs.orga\"",
+		"UserAttrsFirstNamePath  = \"\\\"first name\\\"\"",
+		"var UserAttrs = userAttrs{",
+		"Role: datatypes.NewJSONField[string](\"attributes\", \"role\"),",
+		"Tags: datatypes.NewJSONSliceField[string](\"attributes\", \"tags\"),",
+		"JSONField: datatypes.NewJSONField[Org](\"attributes\", \"owner\"),",
+		"Orga:      datatypes.NewJSONField[string](\"attributes\", \"owner\", \"orga\"),",
+		"Raw:       datatypes.NewJSONField[[]byte](\"attributes\", \"raw\"),",
+		"Parent: datatypes.NewJSONField[Attributes](\"attributes\", \"parent\"),",
+		"type userAttrsOrgs struct {\n\tdatatypes.JSONField[Org]\n",
+	} {
+		if !strings.Contains(string(src), expect) {
+			t.Errorf("generated source should contain %q, got\n%s", expect, src)
+		}
+	}
+
+	for _, unexpect := range []string{"secret", "Ignored"} {
+		if strings.Contains(string(src), unexpect) {
+			t.Errorf("generated source should not contain %q", unexpect)
+		}
+	}
+
+	if src, err := generate("testdata/models", "Org", "orgs", "OrgPaths"); err != nil || strings.Contains(string(src), "\"time\"") {
+		t.Errorf("failed to generate without imports, got error %v\n%s", err, src)
+	}
+
+	if _, err := generate("testdata/models", "Unknown", "attributes", "UserAttrs"); err == nil {
+		t.Errorf("should fail to generate unknown struct")
+	}
+}
+
+// typeCheck type checks the package in dir with the generated source added as file name
+func typeCheck(dir, name string, src []byte) error {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	generated, err := parser.ParseFile(fset, name, src, 0)
+	if err != nil {
+		return err
+	}
+
+	files := []*ast.File{generated}
+	for _, file := range pkgs[generated.Name.Name].Files {
+		files = append(files, file)
+	}
+
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check(generated.Name.Name, fset, files, nil)
+	return err
+}
@@ -0,0 +1,47 @@
+// Command datatypes-gen generates typed json paths of the structs stored in json columns, like JSONType[T] and
+// JSONSlice[T], the paths are built on datatypes.JSONField and datatypes.JSONSliceField without reflection
+//
+//	//go:generate go run gorm.io/datatypes/cmd/datatypes-gen -type Attributes -column attributes -name UserAttrs
+//
+//	DB.Where(UserAttrs.Orgs.Orga.Eq("orga")).Find(&users)
+//	DB.Model(&user).UpdateColumn("attributes", UserAttrs.Tags.Append("tag3"))
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var (
+		typeName = flag.String("type", "", "name of the struct stored in the json column, required")
+		column   = flag.String("column", "", "name of the json column, required")
+		name     = flag.String("name", "", "name of the generated variable, defaults to <type>Paths")
+		dir      = flag.String("dir", ".", "directory of the package of the struct")
+		output   = flag.String("output", "", "output file, defaults to <type>_json_paths.go in dir")
+	)
+	flag.Parse()
+
+	if *typeName == "" || *column == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *name == "" {
+		*name = *typeName + "Paths"
+	}
+	if *output == "" {
+		*output = filepath.Join(*dir, strings.ToLower(*typeName)+"_json_paths.go")
+	}
+
+	src, err := generate(*dir, *typeName, *column, *name)
+	if err == nil {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "datatypes-gen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type Org struct {
+	Orga string `json:"orga"`
+	Size int    `json:"size,omitempty"`
+}
+
+type Base struct {
+	Role string `json:"role"`
+}
+
+type Attributes struct {
+	Base
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	Tags      []string  `json:"tags"`
+	Orgs      Org       `json:"orgs"`
+	Owner     *Org      `json:"owner"`
+	Joined    time.Time `json:"joined"`
+	FirstName string    `json:"first name"`
+	Raw       []byte    `json:"raw"`
+	Meta      struct {
+		Source string `json:"source"`
+	} `json:"meta"`
+	Parent  *Attributes `json:"parent"`
+	secret  string
+	Ignored string `json:"-"`
+}
+
+type User struct {
+	ID         uint
+	Attributes datatypes.JSONType[Attributes]
+}
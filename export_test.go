@@ -0,0 +1,8 @@
+package datatypes
+
+import "gorm.io/gorm"
+
+// SupportsMySQLMemberOf reports if the json array queries of db use MEMBER OF and JSON_OVERLAPS
+func SupportsMySQLMemberOf(db *gorm.DB) bool {
+	return supportsMySQLMemberOf(&gorm.Statement{DB: db})
+}
@@ -3,13 +3,16 @@ package datatypes
 import (
 	"context"
 	"database/sql/driver"
+	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -87,6 +90,8 @@ func (JSON) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
 	}
 	return ""
 }
@@ -109,15 +114,38 @@ func (js JSON) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
 }
 
 // JSONQueryExpression json query expression, implements clause.Expression interface to use as querier
+//
+// Conditions added to the same expression are joined by AND, use And, Or and Not to group them:
+//
+//	JSONQuery("attributes").HasKey("orgs").Or(
+//		JSONQuery("attributes").Equals("admin", "role"),
+//		JSONQuery("attributes").Gt(18, "age"),
+//	)
 type JSONQueryExpression struct {
-	column      string
+	column     string
+	conditions []*jsonQueryCondition
+	extract    bool
+	path       string
+	err        error
+}
+
+// jsonQueryCondition is a condition on keys of the json column, or a group of expressions joined by logic
+type jsonQueryCondition struct {
 	keys        []string
 	hasKeys     bool
 	equals      bool
 	likes       bool
 	equalsValue interface{}
-	extract     bool
-	path        string
+	compare     bool
+	operator    string
+	values      []interface{}
+	contains    bool
+	hasAnyKey   bool
+	hasAllKeys  bool
+	names       []string
+	presence    string
+	logic       string
+	group       []*JSONQueryExpression
 }
 
 // JSONQuery query column as json
@@ -134,319 +162,2459 @@ func (jsonQuery *JSONQueryExpression) Extract(path string) *JSONQueryExpression
 
 // HasKey returns clause.Expression
 func (jsonQuery *JSONQueryExpression) HasKey(keys ...string) *JSONQueryExpression {
-	jsonQuery.keys = keys
-	jsonQuery.hasKeys = true
-	return jsonQuery
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, hasKeys: true})
+}
+
+// KeyExists checks if keys exist, values that are JSON null exist, the same in every dialect
+func (jsonQuery *JSONQueryExpression) KeyExists(keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, presence: "exists"})
+}
+
+// IsJSONNull checks if the value of keys is JSON null
+func (jsonQuery *JSONQueryExpression) IsJSONNull(keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, presence: "null"})
+}
+
+// IsMissing checks if keys don't exist in a column that is not SQL NULL
+func (jsonQuery *JSONQueryExpression) IsMissing(keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, presence: "missing"})
+}
+
+// IsSQLNull checks if the column is SQL NULL
+func (jsonQuery *JSONQueryExpression) IsSQLNull() *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{presence: "sqlnull"})
 }
 
 // Keys returns clause.Expression
 func (jsonQuery *JSONQueryExpression) Equals(value interface{}, keys ...string) *JSONQueryExpression {
-	jsonQuery.keys = keys
-	jsonQuery.equals = true
-	jsonQuery.equalsValue = value
-	return jsonQuery
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, equals: true, equalsValue: value})
 }
 
 // Likes return clause.Expression
 func (jsonQuery *JSONQueryExpression) Likes(value interface{}, keys ...string) *JSONQueryExpression {
-	jsonQuery.keys = keys
-	jsonQuery.likes = true
-	jsonQuery.equalsValue = value
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, likes: true, equalsValue: value})
+}
+
+// Contains checks if the json extracted from keys contains value, which is marshaled to json, objects contain
+// objects with a subset of their fields and arrays contain arrays with a subset of their elements. Uses the
+// index friendly @> operator in PostgreSQL, JSON_CONTAINS in MySQL, this method is not supported by SQL Server
+func (jsonQuery *JSONQueryExpression) Contains(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, contains: true, equalsValue: value})
+}
+
+// JSONContains checks if column contains example, a struct, map or JSONType value. Like struct conditions of gorm,
+// fields of structs with zero value are not used to match, maps are matched with all of their entries
+func JSONContains(column string, example interface{}) *JSONQueryExpression {
+	return JSONQuery(column).Contains(jsonExample(reflect.ValueOf(example)))
+}
+
+// HasAnyKey checks if the json object extracted from keys has any of names as key, uses the ?| operator in PostgreSQL
+func (jsonQuery *JSONQueryExpression) HasAnyKey(names []string, keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, hasAnyKey: true, names: names})
+}
+
+// HasAllKeys checks if the json object extracted from keys has all of names as keys, uses the ?& operator in PostgreSQL
+func (jsonQuery *JSONQueryExpression) HasAllKeys(names []string, keys ...string) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, hasAllKeys: true, names: names})
+}
+
+// NotEquals checks if the value extracted from keys is not equal to value
+func (jsonQuery *JSONQueryExpression) NotEquals(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith("<>", keys, value)
+}
+
+// Gt checks if the value extracted from keys is greater than value
+func (jsonQuery *JSONQueryExpression) Gt(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith(">", keys, value)
+}
+
+// Gte checks if the value extracted from keys is greater than or equal to value
+func (jsonQuery *JSONQueryExpression) Gte(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith(">=", keys, value)
+}
+
+// Lt checks if the value extracted from keys is less than value
+func (jsonQuery *JSONQueryExpression) Lt(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith("<", keys, value)
+}
+
+// Lte checks if the value extracted from keys is less than or equal to value
+func (jsonQuery *JSONQueryExpression) Lte(value interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith("<=", keys, value)
+}
+
+// Between checks if the value extracted from keys is between lower and upper, inclusive
+func (jsonQuery *JSONQueryExpression) Between(lower, upper interface{}, keys ...string) *JSONQueryExpression {
+	return jsonQuery.compareWith("BETWEEN", keys, lower, upper)
+}
+
+// In checks if the value extracted from keys is one of the elements of the slice values
+func (jsonQuery *JSONQueryExpression) In(values interface{}, keys ...string) *JSONQueryExpression {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return jsonQuery.compareWith("IN", keys, values)
+	}
+
+	elems := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return jsonQuery.compareWith("IN", keys, elems...)
+}
+
+// compareWith compares the value extracted from keys with values, the JSON value is cast
+// according to the Go type of the first value: numbers compare numerically, time.Time compares
+// as timestamp, bool compares with JSON true/false and everything else compares as text.
+func (jsonQuery *JSONQueryExpression) compareWith(operator string, keys []string, values ...interface{}) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{keys: keys, compare: true, operator: operator, values: values})
+}
+
+// And adds a group of expressions that must all match, expressions without column use the column of jsonQuery
+func (jsonQuery *JSONQueryExpression) And(exprs ...*JSONQueryExpression) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{logic: "AND", group: exprs})
+}
+
+// Or adds a group of expressions of which at least one must match, expressions without column use the column of jsonQuery
+func (jsonQuery *JSONQueryExpression) Or(exprs ...*JSONQueryExpression) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{logic: "OR", group: exprs})
+}
+
+// Not adds a group of expressions that must not all match, expressions without column use the column of jsonQuery
+func (jsonQuery *JSONQueryExpression) Not(exprs ...*JSONQueryExpression) *JSONQueryExpression {
+	return jsonQuery.where(&jsonQueryCondition{logic: "NOT", group: exprs})
+}
+
+func (jsonQuery *JSONQueryExpression) where(cond *jsonQueryCondition) *JSONQueryExpression {
+	jsonQuery.conditions = append(jsonQuery.conditions, cond)
 	return jsonQuery
 }
 
+// valuesOf reports if the values compared by cond are all of kind, so they can be compared with the generated
+// column or the indexed expression of that kind without casts
+func (cond *jsonQueryCondition) valuesOf(kind jsonValueKind) bool {
+	values := cond.values
+	if cond.equals || cond.likes {
+		values = []interface{}{cond.equalsValue}
+	}
+	if len(values) == 0 || cond.likes && kind != jsonKindText {
+		return false
+	}
+
+	for _, value := range values {
+		if value == nil || jsonKindOf(value) != kind {
+			return false
+		}
+	}
+	return true
+}
+
+// buildIndexed compares the generated column or the indexed expression of the path declared by the jsonindex tag,
+// the json type of the value is checked too, as the column of a string "5" or null is the same as of 5 or "null"
+func (cond *jsonQueryCondition) buildIndexed(stmt *gorm.Statement, src jsonSource, path jsonPath, value clause.Expression, kind jsonValueKind) {
+	stmt.WriteByte('(')
+	value.Build(stmt)
+	switch {
+	case cond.equals:
+		stmt.WriteString(" = ")
+		stmt.AddVar(stmt, cond.equalsValue)
+	case cond.likes:
+		stmt.WriteString(" LIKE ")
+		stmt.AddVar(stmt, cond.equalsValue)
+	case cond.operator == "BETWEEN":
+		stmt.WriteString(" BETWEEN ")
+		stmt.AddVar(stmt, cond.values[0])
+		stmt.WriteString(" AND ")
+		stmt.AddVar(stmt, cond.values[len(cond.values)-1])
+	case cond.operator == "IN":
+		stmt.WriteString(" IN ")
+		stmt.AddVar(stmt, cond.values)
+	default:
+		stmt.WriteString(" " + cond.operator + " ")
+		stmt.AddVar(stmt, cond.values[0])
+	}
+	stmt.WriteString(" AND ")
+	writeJSONTypeIs(stmt, src, path, kind)
+	stmt.WriteByte(')')
+}
+
+// writeJSONTypeIs writes the condition that the json value of src at path is a string or a number by kind
+func writeJSONTypeIs(stmt *gorm.Statement, src jsonSource, path jsonPath, kind jsonValueKind) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_TYPE(")
+		writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+		if kind == jsonKindNumber {
+			stmt.WriteString(") IN ('INTEGER','UNSIGNED INTEGER','DOUBLE','DECIMAL')")
+		} else {
+			stmt.WriteString(") = 'STRING'")
+		}
+	case "sqlite":
+		writeJSONExtract(stmt, "json_type", src, path)
+		if kind == jsonKindNumber {
+			stmt.WriteString(" IN ('integer','real')")
+		} else {
+			stmt.WriteString(" = 'text'")
+		}
+	case "postgres":
+		stmt.WriteString("json_typeof(")
+		writeJSONExtract(stmt, "json_extract_path", src, path)
+		if kind == jsonKindNumber {
+			stmt.WriteString(") = 'number'")
+		} else {
+			stmt.WriteString(") = 'string'")
+		}
+	case "sqlserver":
+		writeOPENJSONType(stmt, src, path)
+		if kind == jsonKindNumber {
+			stmt.WriteString(" = 2")
+		} else {
+			stmt.WriteString(" = 1")
+		}
+	}
+}
+
+func (cond *jsonQueryCondition) buildCompare(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	if len(cond.values) == 0 {
+		return
+	}
+
+	kind := jsonKindOf(cond.values[0])
+	writeJSONTypedValue(stmt, src, path, kind)
+	switch cond.operator {
+	case "BETWEEN":
+		stmt.WriteString(" BETWEEN ")
+		writeJSONTypedVar(stmt, kind, cond.values[0])
+		stmt.WriteString(" AND ")
+		writeJSONTypedVar(stmt, kind, cond.values[len(cond.values)-1])
+	case "IN":
+		stmt.WriteString(" IN (")
+		for idx, value := range cond.values {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			writeJSONTypedVar(stmt, kind, value)
+		}
+		stmt.WriteByte(')')
+	default:
+		stmt.WriteString(" " + cond.operator + " ")
+		writeJSONTypedVar(stmt, kind, cond.values[0])
+	}
+}
+
 // Build implements clause.Expression
 func (jsonQuery *JSONQueryExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
-		case "mysql", "sqlite":
-			switch {
-			case jsonQuery.extract:
-				builder.WriteString("JSON_EXTRACT(")
-				builder.WriteQuoted(jsonQuery.column)
-				builder.WriteByte(',')
-				builder.AddVar(stmt, prefix+jsonQuery.path)
-				builder.WriteString(")")
-			case jsonQuery.hasKeys:
-				if len(jsonQuery.keys) > 0 {
-					builder.WriteString("JSON_EXTRACT(")
-					builder.WriteQuoted(jsonQuery.column)
-					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
-					builder.WriteString(") IS NOT NULL")
+		if jsonQuery.extract {
+			path, err := parseJSONPath(jsonQuery.path)
+			if err != nil {
+				_ = stmt.AddError(err)
+				return
+			}
+
+			src := jsonSource{column: jsonQuery.column}
+			switch stmt.Dialector.Name() {
+			case "mysql":
+				writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			case "sqlite":
+				if len(path.wildcards()) == 0 {
+					writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+					break
 				}
-			case jsonQuery.equals:
-				if len(jsonQuery.keys) > 0 {
-					builder.WriteString("JSON_EXTRACT(")
-					builder.WriteQuoted(jsonQuery.column)
-					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
-					builder.WriteString(") = ")
-					if value, ok := jsonQuery.equalsValue.(bool); ok {
-						builder.WriteString(strconv.FormatBool(value))
-					} else {
-						stmt.AddVar(builder, jsonQuery.equalsValue)
-					}
+
+				// collect the matches of wildcards into an array like MySQL
+				stmt.WriteString("(SELECT json_group_array(")
+				each, rest := jsonEachSource("sqlite", src, path)
+				writeJSONExtract(stmt, "json_extract", each, rest)
+				stmt.WriteByte(')')
+				writeJSONEachFrom(stmt, src, path)
+				stmt.WriteByte(')')
+			case "postgres":
+				if len(path.wildcards()) == 0 {
+					writeJSONExtract(stmt, "json_extract_path_text", src, path)
+					break
 				}
-			case jsonQuery.likes:
-				if len(jsonQuery.keys) > 0 {
-					builder.WriteString("JSON_EXTRACT(")
-					builder.WriteQuoted(jsonQuery.column)
-					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(jsonQuery.keys))
-					builder.WriteString(") LIKE ")
-					if value, ok := jsonQuery.equalsValue.(bool); ok {
-						builder.WriteString(strconv.FormatBool(value))
-					} else {
-						stmt.AddVar(builder, jsonQuery.equalsValue)
-					}
+
+				stmt.WriteString("jsonb_path_query_array(")
+				stmt.WriteQuoted(jsonQuery.column)
+				stmt.WriteString("::jsonb,")
+				stmt.AddVar(stmt, path.jsonpath())
+				stmt.WriteString("::jsonpath)::text")
+			case "sqlserver":
+				if len(path.wildcards()) > 0 || path.has(jsonPathLast) {
+					_ = stmt.AddError(fmt.Errorf("json path %q is not supported by sqlserver", jsonQuery.path))
+					return
 				}
+
+				stmt.WriteString("COALESCE(")
+				writeJSONExtract(stmt, "JSON_VALUE", src, path)
+				stmt.WriteByte(',')
+				writeJSONExtract(stmt, "JSON_QUERY", src, path)
+				stmt.WriteByte(')')
 			}
-		case "postgres":
+			return
+		}
+
+		jsonQuery.buildConditions(stmt, jsonQuery.column)
+	}
+}
+
+// buildConditions writes the conditions joined by AND, column is used when jsonQuery has no column
+func (jsonQuery *JSONQueryExpression) buildConditions(stmt *gorm.Statement, column string) {
+	if jsonQuery.err != nil {
+		_ = stmt.AddError(jsonQuery.err)
+		return
+	}
+
+	if jsonQuery.column != "" {
+		column = jsonQuery.column
+	}
+
+	conditions := make([]*jsonQueryCondition, 0, len(jsonQuery.conditions))
+	for _, cond := range jsonQuery.conditions {
+		if !cond.empty() {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if len(conditions) > 1 {
+		stmt.WriteByte('(')
+	}
+	for idx, cond := range conditions {
+		if idx > 0 {
+			stmt.WriteString(" AND ")
+		}
+		cond.build(stmt, column)
+	}
+	if len(conditions) > 1 {
+		stmt.WriteByte(')')
+	}
+}
+
+func (jsonQuery *JSONQueryExpression) empty() bool {
+	if jsonQuery.err != nil {
+		return false
+	}
+	for _, cond := range jsonQuery.conditions {
+		if !cond.empty() {
+			return false
+		}
+	}
+	return true
+}
+
+func (cond *jsonQueryCondition) empty() bool {
+	if cond.logic != "" {
+		for _, expr := range cond.group {
+			if !expr.empty() {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case cond.contains, cond.presence != "":
+		return false
+	case cond.hasAnyKey, cond.hasAllKeys:
+		return len(cond.names) == 0
+	}
+	return len(cond.keys) == 0 || (cond.compare && len(cond.values) == 0)
+}
+
+func (cond *jsonQueryCondition) buildGroup(stmt *gorm.Statement, column string) {
+	if cond.logic == "NOT" {
+		stmt.WriteString("NOT ")
+	}
+
+	stmt.WriteByte('(')
+	var written bool
+	for _, expr := range cond.group {
+		if expr.empty() {
+			continue
+		}
+		if written {
+			if cond.logic == "OR" {
+				stmt.WriteString(" OR ")
+			} else {
+				stmt.WriteString(" AND ")
+			}
+		}
+		expr.buildConditions(stmt, column)
+		written = true
+	}
+	stmt.WriteByte(')')
+}
+
+func (cond *jsonQueryCondition) build(stmt *gorm.Statement, column string) {
+	if cond.logic != "" {
+		cond.buildGroup(stmt, column)
+		return
+	}
+	if cond.presence == "sqlnull" {
+		stmt.WriteQuoted(column)
+		stmt.WriteString(" IS NULL")
+		return
+	}
+
+	writeJSONEach(stmt, jsonSource{column: column}, parseJSONKeys(cond.keys), func(src jsonSource, path jsonPath) {
+		cond.buildPath(stmt, src, path)
+	})
+}
+
+func (cond *jsonQueryCondition) buildPath(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch {
+	case cond.contains:
+		cond.buildContains(stmt, src, path)
+		return
+	case cond.hasAnyKey, cond.hasAllKeys:
+		cond.buildHasKeys(stmt, src, path)
+		return
+	case cond.presence == "missing":
+		if src.alias == "" {
+			stmt.WriteByte('(')
+			stmt.WriteQuoted(src.column)
+			stmt.WriteString(" IS NOT NULL AND NOT (")
+			writeJSONPresence(stmt, src, path, false)
+			stmt.WriteString("))")
+		} else {
+			stmt.WriteString("NOT (")
+			writeJSONPresence(stmt, src, path, false)
+			stmt.WriteByte(')')
+		}
+		return
+	case cond.presence != "":
+		writeJSONPresence(stmt, src, path, cond.presence == "null")
+		return
+	case cond.equals, cond.likes, cond.compare:
+		if value, kind, ok := jsonIndexValue(stmt, src, path); ok && cond.valuesOf(kind) {
+			cond.buildIndexed(stmt, src, path, value, kind)
+			return
+		}
+	}
+
+	switch stmt.Dialector.Name() {
+	case "mysql", "sqlite":
+		switch {
+		case cond.hasKeys:
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(" IS NOT NULL")
+		case cond.equals, cond.likes:
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			if cond.equals {
+				stmt.WriteString(" = ")
+			} else {
+				stmt.WriteString(" LIKE ")
+			}
+
+			if value, ok := cond.equalsValue.(bool); ok {
+				stmt.WriteString(strconv.FormatBool(value))
+			} else {
+				stmt.AddVar(stmt, cond.equalsValue)
+			}
+		case cond.compare:
+			cond.buildCompare(stmt, src, path)
+		}
+	case "postgres":
+		switch {
+		case cond.hasKeys:
+			if len(path) == 0 || path[len(path)-1].kind != jsonPathKey {
+				writeJSONExtract(stmt, "json_extract_path", src, path)
+				stmt.WriteString(" IS NOT NULL")
+				return
+			}
+
+			writeJSONSource(stmt, src)
+			stmt.WriteString("::jsonb")
+			for _, segment := range path[0 : len(path)-1] {
+				stmt.WriteString(" -> ")
+				switch segment.kind {
+				case jsonPathKey:
+					stmt.AddVar(stmt, segment.key)
+				case jsonPathIndex:
+					stmt.WriteString(strconv.Itoa(segment.index))
+				case jsonPathLast:
+					stmt.WriteString("-1")
+				}
+			}
+
+			stmt.WriteString(" ? ")
+			stmt.AddVar(stmt, path[len(path)-1].key)
+		case cond.equals, cond.likes:
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+			if cond.equals {
+				stmt.WriteString(" = ")
+			} else {
+				stmt.WriteString(" LIKE ")
+			}
+
+			if _, ok := cond.equalsValue.(string); ok {
+				stmt.AddVar(stmt, cond.equalsValue)
+			} else {
+				stmt.AddVar(stmt, fmt.Sprint(cond.equalsValue))
+			}
+		case cond.compare:
+			cond.buildCompare(stmt, src, path)
+		}
+	case "sqlserver":
+		switch {
+		case cond.hasKeys:
+			stmt.WriteByte('(')
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteString(" IS NOT NULL OR ")
+			writeJSONExtract(stmt, "JSON_QUERY", src, path)
+			stmt.WriteString(" IS NOT NULL)")
+		case cond.equals, cond.likes:
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			if cond.equals {
+				stmt.WriteString(" = ")
+			} else {
+				stmt.WriteString(" LIKE ")
+			}
+
+			// JSON_VALUE always returns NVARCHAR, compare with the text form of the value
+			if _, ok := cond.equalsValue.(string); ok {
+				stmt.AddVar(stmt, cond.equalsValue)
+			} else {
+				stmt.AddVar(stmt, fmt.Sprint(cond.equalsValue))
+			}
+		case cond.compare:
+			cond.buildCompare(stmt, src, path)
+		}
+	}
+}
+
+// writeJSONPresence writes the condition that path exists in src, or that its value is JSON null if null is true,
+// JSON null values exist in every dialect
+func writeJSONPresence(stmt *gorm.Statement, src jsonSource, path jsonPath, null bool) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		if null {
+			stmt.WriteString("JSON_TYPE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") = 'NULL'")
+			return
+		}
+		stmt.WriteString("JSON_CONTAINS_PATH(")
+		writeJSONSource(stmt, src)
+		stmt.WriteString(",'one',")
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteByte(')')
+	case "sqlite":
+		writeJSONExtract(stmt, "json_type", src, path)
+		if null {
+			stmt.WriteString(" = 'null'")
+		} else {
+			stmt.WriteString(" IS NOT NULL")
+		}
+	case "postgres":
+		if null {
+			stmt.WriteString("jsonb_typeof(")
+		}
+		writeJSONBPath(stmt, src, path)
+		if null {
+			stmt.WriteString(") = 'null'")
+		} else {
+			stmt.WriteString(" IS NOT NULL")
+		}
+	case "sqlserver":
+		// JSON_VALUE and JSON_QUERY return NULL for JSON null, look up the key in the parent with OPENJSON
+		if len(path) == 0 {
 			switch {
-			case jsonQuery.extract:
-				builder.WriteString(fmt.Sprintf("json_extract_path_text(%v::json,", stmt.Quote(jsonQuery.column)))
-				stmt.AddVar(builder, jsonQuery.path)
-				builder.WriteByte(')')
-			case jsonQuery.hasKeys:
-				if len(jsonQuery.keys) > 0 {
-					stmt.WriteQuoted(jsonQuery.column)
-					stmt.WriteString("::jsonb")
-					for _, key := range jsonQuery.keys[0 : len(jsonQuery.keys)-1] {
-						stmt.WriteString(" -> ")
-						stmt.AddVar(builder, key)
-					}
+			case src.alias != "" && null:
+				stmt.WriteString(src.alias + ".[type] = 0")
+			case src.alias != "":
+				stmt.WriteString("1 = 1")
+			case null:
+				stmt.WriteQuoted(src.column)
+				stmt.WriteString(" = 'null'")
+			default:
+				stmt.WriteQuoted(src.column)
+				stmt.WriteString(" IS NOT NULL")
+			}
+			return
+		}
+
+		stmt.WriteString("EXISTS(SELECT 1 FROM OPENJSON(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path[:len(path)-1])
+		stmt.WriteString(") AS k WHERE k.[key] = ")
+		if last := path[len(path)-1]; last.kind == jsonPathKey {
+			stmt.AddVar(stmt, last.key)
+		} else {
+			stmt.AddVar(stmt, strconv.Itoa(last.index))
+		}
+		if null {
+			stmt.WriteString(" AND k.[type] = 0")
+		}
+		stmt.WriteByte(')')
+	}
+}
+
+func (cond *jsonQueryCondition) buildContains(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	candidate, err := json.Marshal(cond.equalsValue)
+	if err != nil {
+		_ = stmt.AddError(err)
+		return
+	}
+
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_CONTAINS(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		stmt.AddVar(stmt, string(candidate))
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteByte(')')
+	case "sqlite":
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(string(candidate)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			_ = stmt.AddError(err)
+			return
+		}
+		writeSQLiteJSONContains(stmt, src, path, value, new(int))
+	case "postgres":
+		// wrap the candidate into the objects of keys, so the whole column is compared, which can use GIN indexes,
+		// as only top level arrays contain scalars, a wrapped scalar also matches when wrapped into an array
+		candidates := [][]byte{candidate}
+		if scalar := candidate[0] != '{' && candidate[0] != '['; scalar && src.alias == "" && len(path) > 0 && path[len(path)-1].kind == jsonPathKey {
+			candidates = append(candidates, append(append([]byte{'['}, candidate...), ']'))
+		}
+		for len(path) > 0 && src.alias == "" && path[len(path)-1].kind == jsonPathKey {
+			for idx := range candidates {
+				candidates[idx], _ = json.Marshal(map[string]json.RawMessage{path[len(path)-1].key: candidates[idx]})
+			}
+			path = path[:len(path)-1]
+		}
 
-					stmt.WriteString(" ? ")
-					stmt.AddVar(builder, jsonQuery.keys[len(jsonQuery.keys)-1])
+		if len(candidates) > 1 {
+			stmt.WriteByte('(')
+		}
+		for idx, candidate := range candidates {
+			if idx > 0 {
+				stmt.WriteString(" OR ")
+			}
+			writeJSONSource(stmt, src)
+			stmt.WriteString("::jsonb")
+			if len(path) > 0 {
+				stmt.WriteString(" #> ")
+				stmt.AddVar(stmt, path.array())
+				stmt.WriteString("::text[]")
+			}
+			stmt.WriteString(" @> ")
+			stmt.AddVar(stmt, string(candidate))
+			stmt.WriteString("::jsonb")
+		}
+		if len(candidates) > 1 {
+			stmt.WriteByte(')')
+		}
+	default:
+		_ = stmt.AddError(fmt.Errorf("json contains is not supported by %s", stmt.Dialector.Name()))
+	}
+}
+
+// writeSQLiteJSONContains writes the conditions for the json at path to contain the decoded json value,
+// elements of arrays are expanded with json_each, aliases numbers the table functions
+func writeSQLiteJSONContains(stmt *gorm.Statement, src jsonSource, path jsonPath, value interface{}, aliases *int) {
+	writeType := func() {
+		stmt.WriteString("json_type(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteByte(')')
+	}
+
+	nextAlias := func() string {
+		*aliases++
+		return "c" + strconv.Itoa(*aliases)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		stmt.WriteByte('(')
+		writeType()
+		stmt.WriteString(" = 'object'")
+		for _, key := range keys {
+			stmt.WriteString(" AND ")
+			writeSQLiteJSONContains(stmt, src, append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathKey, key: key}), value[key], aliases)
+		}
+		stmt.WriteByte(')')
+	case []interface{}:
+		stmt.WriteByte('(')
+		writeType()
+		stmt.WriteString(" = 'array'")
+		for _, elem := range value {
+			alias := nextAlias()
+			stmt.WriteString(" AND EXISTS(SELECT 1 FROM json_each(")
+			writeJSONSource(stmt, src)
+			stmt.WriteByte(',')
+			writeJSONPathArg(stmt, src, path)
+			stmt.WriteString(") AS " + alias + " WHERE ")
+			writeSQLiteJSONContains(stmt, jsonSource{column: src.column, alias: alias}, nil, elem, aliases)
+			stmt.WriteByte(')')
+		}
+		stmt.WriteByte(')')
+	default:
+		// json_each of a scalar returns the scalar itself, so scalars match equal scalars and arrays containing them
+		alias := nextAlias()
+		stmt.WriteString("EXISTS(SELECT 1 FROM json_each(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteString(") AS " + alias + " WHERE ")
+		writeType()
+		stmt.WriteString(" <> 'object' AND " + alias + ".type ")
+		switch value := value.(type) {
+		case nil:
+			stmt.WriteString("= 'null'")
+		case bool:
+			stmt.WriteString("= '" + strconv.FormatBool(value) + "'")
+		case json.Number:
+			stmt.WriteString("IN ('integer','real') AND " + alias + ".value = ")
+			if v, err := value.Int64(); err == nil {
+				stmt.AddVar(stmt, v)
+			} else {
+				v, _ := value.Float64()
+				stmt.AddVar(stmt, v)
+			}
+		default:
+			stmt.WriteString("= 'text' AND " + alias + ".value = ")
+			stmt.AddVar(stmt, value)
+		}
+		stmt.WriteByte(')')
+	}
+}
+
+func (cond *jsonQueryCondition) buildHasKeys(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_CONTAINS_PATH(")
+		writeJSONSource(stmt, src)
+		if cond.hasAnyKey {
+			stmt.WriteString(",'one'")
+		} else {
+			stmt.WriteString(",'all'")
+		}
+		for _, name := range cond.names {
+			stmt.WriteByte(',')
+			writeJSONPathArg(stmt, src, append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathKey, key: name}))
+		}
+		stmt.WriteByte(')')
+	case "postgres":
+		writeJSONSource(stmt, src)
+		stmt.WriteString("::jsonb")
+		if len(path) > 0 {
+			stmt.WriteString(" #> ")
+			stmt.AddVar(stmt, path.array())
+			stmt.WriteString("::text[]")
+		}
+		if cond.hasAnyKey {
+			stmt.WriteString(" ?| ")
+		} else {
+			stmt.WriteString(" ?& ")
+		}
+
+		names := make(jsonPath, 0, len(cond.names))
+		for _, name := range cond.names {
+			names = append(names, jsonPathSegment{kind: jsonPathKey, key: name})
+		}
+		stmt.AddVar(stmt, names.array())
+		stmt.WriteString("::text[]")
+	default:
+		// combine the conditions of HasKey for every name
+		stmt.WriteByte('(')
+		for idx, name := range cond.names {
+			if idx > 0 {
+				if cond.hasAnyKey {
+					stmt.WriteString(" OR ")
+				} else {
+					stmt.WriteString(" AND ")
 				}
-			case jsonQuery.equals:
-				if len(jsonQuery.keys) > 0 {
-					builder.WriteString(fmt.Sprintf("json_extract_path_text(%v::json,", stmt.Quote(jsonQuery.column)))
+			}
+			(&jsonQueryCondition{hasKeys: true}).buildPath(stmt, src, append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathKey, key: name}))
+		}
+		stmt.WriteByte(')')
+	}
+}
 
-					for idx, key := range jsonQuery.keys {
-						if idx > 0 {
-							builder.WriteByte(',')
-						}
-						stmt.AddVar(builder, key)
-					}
-					builder.WriteString(") = ")
+// jsonValueKind is the type a JSON value is cast to before comparing it with a Go value
+type jsonValueKind int
+
+const (
+	jsonKindText jsonValueKind = iota
+	jsonKindNumber
+	jsonKindBool
+	jsonKindTime
+)
+
+func jsonKindOf(value interface{}) jsonValueKind {
+	switch value.(type) {
+	case time.Time, *time.Time:
+		return jsonKindTime
+	case json.Number:
+		return jsonKindNumber
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return jsonKindNumber
+	case reflect.Bool:
+		return jsonKindBool
+	}
+	return jsonKindText
+}
+
+// writeJSONTypedValue writes the value of src at path cast to kind, the result is NULL
+// when the JSON value is missing or of another type, so comparisons never mix types
+func writeJSONTypedValue(stmt *gorm.Statement, src jsonSource, path jsonPath, kind jsonValueKind) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		switch kind {
+		case jsonKindNumber:
+			stmt.WriteString("CASE WHEN JSON_TYPE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") IN ('INTEGER','UNSIGNED INTEGER','DOUBLE','DECIMAL') THEN CAST(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(" AS DECIMAL(65,30)) END")
+		case jsonKindTime:
+			stmt.WriteString("CASE WHEN JSON_TYPE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") = 'STRING' THEN CAST(JSON_UNQUOTE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") AS DATETIME(6)) END")
+		case jsonKindBool:
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+		default:
+			stmt.WriteString("JSON_UNQUOTE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteByte(')')
+		}
+	case "sqlite":
+		switch kind {
+		case jsonKindNumber:
+			stmt.WriteString("CASE WHEN ")
+			writeJSONExtract(stmt, "json_type", src, path)
+			stmt.WriteString(" IN ('integer','real') THEN ")
+			writeJSONExtract(stmt, "json_extract", src, path)
+			stmt.WriteString(" END")
+		case jsonKindTime:
+			stmt.WriteString("CASE WHEN ")
+			writeJSONExtract(stmt, "json_type", src, path)
+			stmt.WriteString(" = 'text' THEN julianday(")
+			writeJSONExtract(stmt, "json_extract", src, path)
+			stmt.WriteString(") END")
+		case jsonKindBool:
+			writeJSONExtract(stmt, "json_type", src, path)
+		default:
+			writeJSONExtract(stmt, "json_extract", src, path)
+		}
+	case "postgres":
+		switch kind {
+		case jsonKindNumber:
+			stmt.WriteString("CASE WHEN json_typeof(")
+			writeJSONExtract(stmt, "json_extract_path", src, path)
+			stmt.WriteString(") = 'number' THEN ")
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+			stmt.WriteString("::numeric END")
+		case jsonKindTime:
+			stmt.WriteString("CASE WHEN ")
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+			stmt.WriteString(` ~ '^\d{4}-\d{2}-\d{2}' THEN `)
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+			stmt.WriteString("::timestamptz END")
+		case jsonKindBool:
+			writeJSONExtract(stmt, "json_extract_path", src, path)
+			stmt.WriteString("::text")
+		default:
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+		}
+	case "sqlserver":
+		// JSON_VALUE unquotes strings, the OPENJSON type tells them apart from numbers and booleans
+		switch kind {
+		case jsonKindNumber:
+			stmt.WriteString("CASE WHEN ")
+			writeOPENJSONType(stmt, src, path)
+			stmt.WriteString(" = 2 THEN TRY_CAST(")
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteString(" AS FLOAT) END")
+		case jsonKindTime:
+			stmt.WriteString("CASE WHEN ")
+			writeOPENJSONType(stmt, src, path)
+			stmt.WriteString(" = 1 THEN TRY_CAST(")
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteString(" AS DATETIMEOFFSET) END")
+		case jsonKindBool:
+			stmt.WriteString("CASE WHEN ")
+			writeOPENJSONType(stmt, src, path)
+			stmt.WriteString(" = 3 THEN ")
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteString(" END")
+		default:
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+		}
+	}
+}
+
+// writeOPENJSONType writes the OPENJSON type of the value of src at path, 1 for strings, 2 for numbers
+// and 3 for booleans, it is read from the parent of the value by its key
+func writeOPENJSONType(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	if len(path) == 0 {
+		stmt.WriteString(src.alias + ".[type]")
+		return
+	}
+
+	stmt.WriteString("(SELECT [type] FROM OPENJSON(")
+	writeJSONSource(stmt, src)
+	stmt.WriteByte(',')
+	writeJSONPathArg(stmt, src, path[:len(path)-1])
+	stmt.WriteString(") WHERE [key] = ")
+	stmt.AddVar(stmt, path[len(path)-1:].keys()[0])
+	stmt.WriteByte(')')
+}
+
+// writeJSONTypedVar writes value as a bind variable comparable with writeJSONTypedValue of kind
+func writeJSONTypedVar(stmt *gorm.Statement, kind jsonValueKind, value interface{}) {
+	switch kind {
+	case jsonKindBool:
+		b, _ := value.(bool)
+		if v, ok := value.(*bool); ok && v != nil {
+			b = *v
+		}
+		if stmt.Dialector.Name() == "mysql" {
+			stmt.WriteString(strconv.FormatBool(b))
+		} else {
+			stmt.AddVar(stmt, strconv.FormatBool(b))
+		}
+	case jsonKindTime:
+		if stmt.Dialector.Name() == "sqlite" {
+			stmt.WriteString("julianday(")
+			stmt.AddVar(stmt, value)
+			stmt.WriteByte(')')
+		} else {
+			stmt.AddVar(stmt, value)
+		}
+	case jsonKindNumber:
+		if v, ok := value.(json.Number); ok {
+			if stmt.Dialector.Name() == "postgres" {
+				stmt.AddVar(stmt, gorm.Expr("?::numeric", v.String()))
+			} else {
+				stmt.AddVar(stmt, v.String())
+			}
+		} else {
+			stmt.AddVar(stmt, value)
+		}
+	default:
+		if _, ok := value.(string); ok || stmt.Dialector.Name() == "mysql" || stmt.Dialector.Name() == "sqlite" {
+			stmt.AddVar(stmt, value)
+		} else {
+			stmt.AddVar(stmt, fmt.Sprint(value))
+		}
+	}
+}
+
+// writeJSONExtract writes fn(src, path) for MySQL, SQLite and SQL Server, and fn(src::json, keys...)
+// for PostgreSQL
+func writeJSONExtract(stmt *gorm.Statement, fn string, src jsonSource, path jsonPath) {
+	switch stmt.Dialector.Name() {
+	case "postgres":
+		if len(path) == 0 {
+			// the element of a wildcard itself
+			if fn == "json_extract_path_text" {
+				stmt.WriteByte('(')
+				writeJSONSource(stmt, src)
+				stmt.WriteString("::json #>> '{}')")
+			} else {
+				writeJSONSource(stmt, src)
+				stmt.WriteString("::json")
+			}
+			return
+		}
+
+		stmt.WriteString(fn)
+		stmt.WriteByte('(')
+		writeJSONSource(stmt, src)
+		stmt.WriteString("::json")
+		for _, key := range path.keys() {
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, key)
+		}
+		stmt.WriteByte(')')
+	case "sqlserver":
+		if len(path) == 0 && src.alias != "" {
+			// the element of a wildcard itself, OPENJSON returns scalars unquoted
+			if fn == "JSON_VALUE" {
+				stmt.WriteString("CASE WHEN " + src.alias + ".[type] NOT IN (4,5) THEN " + src.alias + ".[value] END")
+			} else {
+				writeJSONSource(stmt, src)
+			}
+			return
+		}
+		fallthrough
+	default:
+		stmt.WriteString(fn)
+		stmt.WriteByte('(')
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteByte(')')
+	}
+}
+
+// JSONExtractExpression typed json value extraction, the value is unquoted and cast to the same type in every
+// dialect, values that are missing or of another type are NULL, implements clause.Expression interface to use
+// as select column, e.g.
+//
+//	DB.Model(&User{}).Select("name, ?", JSONQuery("attributes").AsInt("score").As("score")).Find(&results)
+//
+// as order by term
+//
+//	DB.Order(clause.OrderBy{Expression: JSONQuery("attributes").AsInt("score").Desc().NullsLast()}).Find(&users)
+//
+// or as group by key with its alias
+//
+//	DB.Model(&User{}).Select("?, count(*) AS total", JSONQuery("attributes").AsText("role").As("role")).Group("role").Find(&results)
+type JSONExtractExpression struct {
+	column string
+	path   string
+	kind   string
+	alias  string
+	desc   bool
+	nulls  string
+}
+
+// AsText extracts the value at path as text, strings are unquoted, booleans are `true` or `false`
+func (jsonQuery *JSONQueryExpression) AsText(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "text"}
+}
+
+// AsInt extracts the number at path as integer, the fraction is truncated
+func (jsonQuery *JSONQueryExpression) AsInt(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "int"}
+}
+
+// AsFloat extracts the number at path as float
+func (jsonQuery *JSONQueryExpression) AsFloat(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "float"}
+}
+
+// AsBool extracts the boolean at path
+func (jsonQuery *JSONQueryExpression) AsBool(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "bool"}
+}
+
+// AsTime extracts the timestamp string at path as time, SQLite returns the time as text in UTC, e.g.
+// `2006-01-02 15:04:05.000`, which sorts chronologically
+func (jsonQuery *JSONQueryExpression) AsTime(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "time"}
+}
+
+// Length returns the number of elements of the array at path, NULL if the value is not an array
+func (jsonQuery *JSONQueryExpression) Length(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "length"}
+}
+
+// Type returns the type of the value at path, one of `object`, `array`, `string`, `number`, `boolean` and `null`,
+// NULL if the value is missing
+func (jsonQuery *JSONQueryExpression) Type(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "type"}
+}
+
+// Keys returns the keys of the object at path as JSON array, ordered by length, then bytewise like MySQL and
+// PostgreSQL store them, NULL if the value is not an object
+func (jsonQuery *JSONQueryExpression) Keys(path string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: jsonQuery.column, path: path, kind: "keys"}
+}
+
+// As sets the alias of the select column
+func (extract *JSONExtractExpression) As(alias string) *JSONExtractExpression {
+	extract.alias = alias
+	return extract
+}
+
+// Desc sorts the order by term descending
+func (extract *JSONExtractExpression) Desc() *JSONExtractExpression {
+	extract.desc = true
+	return extract
+}
+
+// NullsFirst sorts NULL before other values, MySQL and SQL Server sort by an additional IS NULL term
+func (extract *JSONExtractExpression) NullsFirst() *JSONExtractExpression {
+	extract.nulls = "FIRST"
+	return extract
+}
+
+// NullsLast sorts NULL after other values, MySQL and SQL Server sort by an additional IS NULL term
+func (extract *JSONExtractExpression) NullsLast() *JSONExtractExpression {
+	extract.nulls = "LAST"
+	return extract
+}
+
+// Build implements clause.Expression
+func (extract *JSONExtractExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	path, err := parseJSONPath(extract.path)
+	switch {
+	case err != nil:
+	case path.has(jsonPathAny):
+		err = fmt.Errorf("invalid json path %q: wildcards are not supported by typed extraction", extract.path)
+	case path.has(jsonPathLast) && stmt.Dialector.Name() == "sqlserver":
+		err = fmt.Errorf("json path %q is not supported by sqlserver", extract.path)
+	}
+	if err != nil {
+		_ = stmt.AddError(err)
+		return
+	}
+
+	src := jsonSource{column: extract.column}
+	if extract.nulls != "" && stmt.Dialector.Name() != "postgres" && stmt.Dialector.Name() != "sqlite" {
+		stmt.WriteString("CASE WHEN ")
+		extract.writeValue(stmt, src, path)
+		if extract.nulls == "FIRST" {
+			stmt.WriteString(" IS NULL THEN 0 ELSE 1 END, ")
+		} else {
+			stmt.WriteString(" IS NULL THEN 1 ELSE 0 END, ")
+		}
+	}
+
+	extract.writeValue(stmt, src, path)
+	if extract.desc {
+		stmt.WriteString(" DESC")
+	}
+	if extract.nulls != "" && (stmt.Dialector.Name() == "postgres" || stmt.Dialector.Name() == "sqlite") {
+		stmt.WriteString(" NULLS " + extract.nulls)
+	}
+	if extract.alias != "" {
+		stmt.WriteString(" AS ")
+		stmt.WriteQuoted(extract.alias)
+	}
+}
+
+func (extract *JSONExtractExpression) writeValue(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch extract.kind {
+	case "int":
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString("CAST(TRUNCATE(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(",0) AS SIGNED)")
+		case "sqlite":
+			stmt.WriteString("CAST(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(" AS INTEGER)")
+		case "postgres":
+			stmt.WriteString("trunc(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(")::bigint")
+		case "sqlserver":
+			stmt.WriteString("CAST(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(" AS BIGINT)")
+		}
+	case "float":
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString("CAST(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(" AS DOUBLE)")
+		case "sqlite":
+			stmt.WriteString("CAST(")
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(" AS REAL)")
+		case "postgres":
+			stmt.WriteByte('(')
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+			stmt.WriteString(")::double precision")
+		default:
+			writeJSONTypedValue(stmt, src, path, jsonKindNumber)
+		}
+	case "bool":
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString("CASE WHEN JSON_TYPE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") = 'BOOLEAN' THEN JSON_UNQUOTE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") = 'true' END")
+		case "sqlite":
+			stmt.WriteString("CASE ")
+			writeJSONExtract(stmt, "json_type", src, path)
+			stmt.WriteString(" WHEN 'true' THEN 1 WHEN 'false' THEN 0 END")
+		case "postgres":
+			stmt.WriteString("CASE WHEN json_typeof(")
+			writeJSONExtract(stmt, "json_extract_path", src, path)
+			stmt.WriteString(") = 'boolean' THEN ")
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+			stmt.WriteString("::boolean END")
+		case "sqlserver":
+			stmt.WriteString("CASE ")
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteString(" WHEN 'true' THEN CAST(1 AS BIT) WHEN 'false' THEN CAST(0 AS BIT) END")
+		}
+	case "length":
+		writeJSONLength(stmt, src, path)
+	case "type":
+		writeJSONType(stmt, src, path)
+	case "keys":
+		writeJSONKeys(stmt, src, path)
+	case "time":
+		if stmt.Dialector.Name() == "sqlite" {
+			stmt.WriteString("CASE WHEN ")
+			writeJSONExtract(stmt, "json_type", src, path)
+			stmt.WriteString(" = 'text' THEN strftime('%Y-%m-%d %H:%M:%f',")
+			writeJSONExtract(stmt, "json_extract", src, path)
+			stmt.WriteString(") END")
+			return
+		}
+		writeJSONTypedValue(stmt, src, path, jsonKindTime)
+	default:
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString("CASE WHEN JSON_TYPE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") <> 'NULL' THEN JSON_UNQUOTE(")
+			writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+			stmt.WriteString(") END")
+		case "sqlite":
+			// json_extract returns booleans as 1 and 0
+			stmt.WriteString("CASE ")
+			writeJSONExtract(stmt, "json_type", src, path)
+			stmt.WriteString(" WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE CAST(")
+			writeJSONExtract(stmt, "json_extract", src, path)
+			stmt.WriteString(" AS TEXT) END")
+		case "postgres":
+			writeJSONExtract(stmt, "json_extract_path_text", src, path)
+		case "sqlserver":
+			stmt.WriteString("COALESCE(")
+			writeJSONExtract(stmt, "JSON_VALUE", src, path)
+			stmt.WriteByte(',')
+			writeJSONExtract(stmt, "JSON_QUERY", src, path)
+			stmt.WriteByte(')')
+		}
+	}
+}
+
+func writeJSONLength(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("CASE WHEN JSON_TYPE(")
+		writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+		stmt.WriteString(") = 'ARRAY' THEN ")
+		writeJSONExtract(stmt, "JSON_LENGTH", src, path)
+		stmt.WriteString(" END")
+	case "sqlite":
+		stmt.WriteString("CASE WHEN ")
+		writeJSONExtract(stmt, "json_type", src, path)
+		stmt.WriteString(" = 'array' THEN ")
+		writeJSONExtract(stmt, "json_array_length", src, path)
+		stmt.WriteString(" END")
+	case "postgres":
+		stmt.WriteString("CASE WHEN jsonb_typeof(")
+		writeJSONBPath(stmt, src, path)
+		stmt.WriteString(") = 'array' THEN jsonb_array_length(")
+		writeJSONBPath(stmt, src, path)
+		stmt.WriteString(") END")
+	case "sqlserver":
+		stmt.WriteString("CASE WHEN LEFT(LTRIM(")
+		writeJSONExtract(stmt, "JSON_QUERY", src, path)
+		stmt.WriteString("),1) = '[' THEN (SELECT COUNT(*) FROM OPENJSON(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteString(")) END")
+	}
+}
+
+func writeJSONType(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("CASE JSON_TYPE(")
+		writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+		stmt.WriteString(") WHEN 'INTEGER' THEN 'number' WHEN 'UNSIGNED INTEGER' THEN 'number' WHEN 'DOUBLE' THEN 'number'")
+		stmt.WriteString(" WHEN 'DECIMAL' THEN 'number' WHEN 'BOOLEAN' THEN 'boolean' ELSE LOWER(JSON_TYPE(")
+		writeJSONExtract(stmt, "JSON_EXTRACT", src, path)
+		stmt.WriteString(")) END")
+	case "sqlite":
+		stmt.WriteString("CASE ")
+		writeJSONExtract(stmt, "json_type", src, path)
+		stmt.WriteString(" WHEN 'text' THEN 'string' WHEN 'integer' THEN 'number' WHEN 'real' THEN 'number'")
+		stmt.WriteString(" WHEN 'true' THEN 'boolean' WHEN 'false' THEN 'boolean' ELSE ")
+		writeJSONExtract(stmt, "json_type", src, path)
+		stmt.WriteString(" END")
+	case "postgres":
+		stmt.WriteString("jsonb_typeof(")
+		writeJSONBPath(stmt, src, path)
+		stmt.WriteByte(')')
+	case "sqlserver":
+		// look up the type in the parent with OPENJSON, the column itself is wrapped into an array
+		stmt.WriteString("(SELECT CASE k.[type] WHEN 0 THEN 'null' WHEN 1 THEN 'string' WHEN 2 THEN 'number'")
+		stmt.WriteString(" WHEN 3 THEN 'boolean' WHEN 4 THEN 'array' WHEN 5 THEN 'object' END FROM OPENJSON(")
+		if len(path) == 0 {
+			stmt.WriteString("CONCAT('[',")
+			writeJSONSource(stmt, src)
+			stmt.WriteString(",']')) AS k)")
+			return
+		}
+
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path[:len(path)-1])
+		stmt.WriteString(") AS k WHERE k.[key] = ")
+		if last := path[len(path)-1]; last.kind == jsonPathKey {
+			stmt.AddVar(stmt, last.key)
+		} else {
+			stmt.AddVar(stmt, strconv.Itoa(last.index))
+		}
+		stmt.WriteByte(')')
+	}
+}
+
+func writeJSONKeys(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		writeJSONExtract(stmt, "JSON_KEYS", src, path)
+	case "sqlite":
+		stmt.WriteString("CASE WHEN ")
+		writeJSONExtract(stmt, "json_type", src, path)
+		stmt.WriteString(" = 'object' THEN (SELECT json_group_array(key) FROM (SELECT key FROM json_each(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteString(") ORDER BY length(CAST(key AS BLOB)), CAST(key AS BLOB))) END")
+	case "postgres":
+		stmt.WriteString("CASE WHEN jsonb_typeof(")
+		writeJSONBPath(stmt, src, path)
+		stmt.WriteString(") = 'object' THEN COALESCE((SELECT jsonb_agg(k) FROM jsonb_object_keys(")
+		writeJSONBPath(stmt, src, path)
+		stmt.WriteString(") AS k),'[]') END")
+	case "sqlserver":
+		stmt.WriteString("CASE WHEN LEFT(LTRIM(")
+		writeJSONExtract(stmt, "JSON_QUERY", src, path)
+		stmt.WriteString("),1) = '{' THEN (SELECT CONCAT('[',STRING_AGG(CONCAT('\"',STRING_ESCAPE(k.[key],'json'),'\"'),',')")
+		stmt.WriteString(" WITHIN GROUP (ORDER BY DATALENGTH(k.[key]), CAST(k.[key] AS VARBINARY(MAX))),']') FROM OPENJSON(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteString(") AS k) END")
+	}
+}
+
+// writeJSONBPath writes the jsonb value of src at path for PostgreSQL
+func writeJSONBPath(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	writeJSONSource(stmt, src)
+	stmt.WriteString("::jsonb")
+	if len(path) > 0 {
+		stmt.WriteString(" #> ")
+		stmt.AddVar(stmt, path.array())
+		stmt.WriteString("::text[]")
+	}
+}
+
+// JSONOverlapsExpression JSON_OVERLAPS expression, implements clause.Expression interface to use as querier
+type JSONOverlapsExpression struct {
+	column clause.Expression
+	val    string
+}
+
+// JSONOverlaps query column as json, matches when the column and value share at least one array element,
+// scalars are compared as arrays of one element and objects overlap when they share a key-value pair.
+// Nested objects and arrays are compared as json text in SQLite and SQL Server
+//
+//	DB.Where(JSONOverlaps(Column("tags"), `["tag1","tag2"]`))
+func JSONOverlaps(column clause.Expression, value string) *JSONOverlapsExpression {
+	return &JSONOverlapsExpression{
+		column: column,
+		val:    value,
+	}
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (json *JSONOverlapsExpression) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			builder.WriteString("JSON_OVERLAPS(")
+			json.column.Build(builder)
+			builder.WriteString(",")
+			builder.AddVar(stmt, json.val)
+			builder.WriteString(")")
+
+		case "sqlite":
+			// json_each expands arrays and scalars, objects are wrapped to be compared as a whole
+			elements := func(write func()) {
+				builder.WriteString("json_each(CASE WHEN json_type(")
+				write()
+				builder.WriteString(") = 'object' THEN json_array(json(")
+				write()
+				builder.WriteString(")) ELSE ")
+				write()
+				builder.WriteString(" END)")
+			}
+			json.build(stmt, "json_type(", ") = 'object'", func(members bool) {
+				builder.WriteString("EXISTS(SELECT 1 FROM ")
+				if members {
+					builder.WriteString("json_each(")
+					json.column.Build(builder)
+					builder.WriteString(") AS a, json_each(")
+					builder.AddVar(stmt, json.val)
+					builder.WriteString(") AS b WHERE a.key = b.key AND ")
+				} else {
+					elements(func() { json.column.Build(builder) })
+					builder.WriteString(" AS a, ")
+					elements(func() { builder.AddVar(stmt, json.val) })
+					builder.WriteString(" AS b WHERE ")
+				}
+				builder.WriteString("(a.type = b.type OR a.type IN ('integer','real') AND b.type IN ('integer','real')) AND a.value IS b.value)")
+			})
+
+		case "postgres":
+			// lax $[*] returns the elements of arrays and the value itself otherwise
+			json.build(stmt, "jsonb_typeof(", "::jsonb) = 'object'", func(members bool) {
+				builder.WriteString("EXISTS(SELECT 1 FROM ")
+				if members {
+					builder.WriteString("jsonb_each(")
+					json.column.Build(builder)
+					builder.WriteString("::jsonb) AS a WHERE ")
+					builder.AddVar(stmt, json.val)
+					builder.WriteString("::jsonb -> a.key = a.value)")
+				} else {
+					builder.WriteString("jsonb_path_query(")
+					json.column.Build(builder)
+					builder.WriteString("::jsonb,'lax $[*]') AS a WHERE a IN (SELECT jsonb_path_query(")
+					builder.AddVar(stmt, json.val)
+					builder.WriteString("::jsonb,'lax $[*]')))")
+				}
+			})
+
+		case "sqlserver":
+			// OPENJSON fails on scalars, so everything but arrays is wrapped to be compared as a whole
+			elements := func(write func()) {
+				builder.WriteString("OPENJSON(CASE WHEN LEFT(LTRIM(")
+				write()
+				builder.WriteString("),1) = '[' THEN ")
+				write()
+				builder.WriteString(" ELSE CONCAT('[',")
+				write()
+				builder.WriteString(",']') END)")
+			}
+			json.build(stmt, "LEFT(LTRIM(", "),1) = '{'", func(members bool) {
+				builder.WriteString("EXISTS(SELECT 1 FROM ")
+				if members {
+					builder.WriteString("OPENJSON(")
+					json.column.Build(builder)
+					builder.WriteString(") AS a INNER JOIN OPENJSON(")
+					builder.AddVar(stmt, json.val)
+					builder.WriteString(") AS b ON a.[key] = b.[key] AND ")
+				} else {
+					elements(func() { json.column.Build(builder) })
+					builder.WriteString(" AS a INNER JOIN ")
+					elements(func() { builder.AddVar(stmt, json.val) })
+					builder.WriteString(" AS b ON ")
+				}
+				builder.WriteString("a.[type] = b.[type] AND a.[value] = b.[value])")
+			})
+
+		default:
+			_ = stmt.AddError(fmt.Errorf("json overlaps is not supported by %s", stmt.Dialector.Name()))
+		}
+	}
+}
+
+// build writes the overlap of elements, or of members when both the column and the value are objects,
+// isObject is written around the column and the value to test them
+func (json *JSONOverlapsExpression) build(stmt *gorm.Statement, isObject, isObjectEnd string, write func(members bool)) {
+	stmt.WriteByte('(')
+	write(false)
+	stmt.WriteString(" OR " + isObject)
+	json.column.Build(stmt)
+	stmt.WriteString(isObjectEnd + " AND " + isObject)
+	stmt.AddVar(stmt, json.val)
+	stmt.WriteString(isObjectEnd + " AND ")
+	write(true)
+	stmt.WriteByte(')')
+}
+
+type columnExpression string
+
+func Column(col string) columnExpression {
+	return columnExpression(col)
+}
+
+func (col columnExpression) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		switch stmt.Dialector.Name() {
+		case "mysql", "sqlite", "postgres", "sqlserver":
+			builder.WriteString(stmt.Quote(string(col)))
+		}
+	}
+}
+
+// JSONSetExpression json set expression, implements clause.Expression interface to use as updater
+type JSONSetExpression struct {
+	column   string
+	function string
+	paths    []string
+	values   []interface{}
+	mutex    sync.RWMutex
+}
+
+// JSONSet update fields of json column
+func JSONSet(column string) *JSONSetExpression {
+	return &JSONSetExpression{column: column, function: "JSON_SET"}
+}
+
+// JSONInsert insert fields of json column, paths that already exist are left unchanged
+func JSONInsert(column string) *JSONSetExpression {
+	return &JSONSetExpression{column: column, function: "JSON_INSERT"}
+}
+
+// JSONReplace replace fields of json column, paths that don't exist are not created
+func JSONReplace(column string) *JSONSetExpression {
+	return &JSONSetExpression{column: column, function: "JSON_REPLACE"}
+}
+
+// Set return clause.Expression, paths are applied in the order they are set, so later sets win over
+// earlier ones, e.g. setting `orgs` after `orgs.orga` replaces the whole object.
+//
+//	{
+//		"age": 20,
+//		"name": "json-1",
+//		"orgs": {"orga": "orgv"},
+//		"tags": ["tag1", "tag2"]
+//	}
+//
+//	// path is `age`, `name`, `orgs.orga`, `tags[0]`, `tags[1]` for every database.
+//	DB.UpdateColumn("attr", JSONSet("attr").Set("orgs.orga", 42))
+//
+//	// The native forms like `$.orgs.orga` and `{orgs, orga}` in PostgreSQL are still accepted.
+//	DB.UpdateColumn("attr", JSONSet("attr").Set("{orgs, orga}", "bar"))
+func (jsonSet *JSONSetExpression) Set(path string, value interface{}) *JSONSetExpression {
+	jsonSet.mutex.Lock()
+	jsonSet.paths = append(jsonSet.paths, path)
+	jsonSet.values = append(jsonSet.values, value)
+	jsonSet.mutex.Unlock()
+	return jsonSet
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (jsonSet *JSONSetExpression) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		switch stmt.Dialector.Name() {
+		case "mysql", "sqlite":
+			builder.WriteString(jsonSet.function + "(")
+			builder.WriteQuoted(jsonSet.column)
+			for idx, path := range jsonSet.paths {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, jsonUpdatePath(stmt, path))
+				builder.WriteByte(',')
+				writeJSONValue(stmt, jsonSet.values[idx])
+			}
+			builder.WriteString(")")
+
+		case "postgres":
+			var expr clause.Expression = columnExpression(jsonSet.column)
+			for idx, path := range jsonSet.paths {
+				value := jsonSet.values[idx]
+				if _, ok = value.(clause.Expression); !ok {
+					b, _ := json.Marshal(value)
+					value = string(b)
+				}
+
+				path = jsonUpdatePath(stmt, path)
+				switch jsonSet.function {
+				case "JSON_INSERT":
+					// the previous document is read twice, so it is bound once to keep the sql linear
+					expr = gorm.Expr("(SELECT JSONB_SET(d.doc,?,COALESCE(d.doc #> ?,?)) FROM (SELECT ? AS doc) AS d)", path, path, value, expr)
+				case "JSON_REPLACE":
+					expr = gorm.Expr("JSONB_SET(?,?,?,false)", expr, path, value)
+				default:
+					expr = gorm.Expr("JSONB_SET(?,?,?)", expr, path, value)
+				}
+			}
+			stmt.AddVar(builder, expr)
+
+		case "sqlserver":
+			if jsonSet.function != "JSON_SET" {
+				_ = stmt.AddError(fmt.Errorf("%s is not supported by sqlserver", jsonSet.function))
+				return
+			}
+
+			for range jsonSet.paths {
+				builder.WriteString("JSON_MODIFY(")
+			}
+			builder.WriteQuoted(jsonSet.column)
+			for idx, path := range jsonSet.paths {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, jsonUpdatePath(stmt, path))
+				builder.WriteByte(',')
+				writeJSONValue(stmt, jsonSet.values[idx])
+				builder.WriteByte(')')
+			}
+		}
+	}
+}
+
+// writeJSONValue writes value as argument of functions like JSON_SET, slices, arrays, structs and maps
+// are marshaled to json, in PostgreSQL every value is marshaled to jsonb
+func writeJSONValue(stmt *gorm.Statement, value interface{}) {
+	if _, ok := value.(clause.Expression); ok {
+		stmt.AddVar(stmt, value)
+		return
+	}
+
+	if stmt.Dialector.Name() == "postgres" {
+		b, _ := json.Marshal(value)
+		stmt.AddVar(stmt, string(b))
+		stmt.WriteString("::jsonb")
+		return
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
+		b, _ := json.Marshal(value)
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			if v, ok := stmt.Dialector.(*mysql.Dialector); ok && strings.Contains(v.ServerVersion, "MariaDB") {
+				stmt.AddVar(stmt, string(b))
+			} else {
+				stmt.AddVar(stmt, gorm.Expr("CAST(? AS JSON)", string(b)))
+			}
+		case "sqlite":
+			stmt.AddVar(stmt, gorm.Expr("JSON(?)", string(b)))
+		case "sqlserver":
+			stmt.AddVar(stmt, gorm.Expr("JSON_QUERY(?)", string(b)))
+		}
+	case reflect.Bool:
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString(strconv.FormatBool(rv.Bool()))
+		case "sqlserver":
+			if rv.Bool() {
+				stmt.WriteString("CAST(1 AS BIT)")
+			} else {
+				stmt.WriteString("CAST(0 AS BIT)")
+			}
+		default:
+			stmt.AddVar(stmt, value)
+		}
+	default:
+		stmt.AddVar(stmt, value)
+	}
+}
+
+// JSONRemoveExpression json remove expression, implements clause.Expression interface to use as updater
+type JSONRemoveExpression struct {
+	column string
+	paths  []string
+	mutex  sync.RWMutex
+}
+
+// JSONRemove remove fields of json column
+func JSONRemove(column string) *JSONRemoveExpression {
+	return &JSONRemoveExpression{column: column}
+}
+
+// Remove return clause.Expression, path is the same as JSONSetExpression.Set
+//
+//	DB.UpdateColumn("attr", JSONRemove("attr").Remove("orgs.orga").Remove("tags[0]"))
+func (jsonRemove *JSONRemoveExpression) Remove(path string) *JSONRemoveExpression {
+	jsonRemove.mutex.Lock()
+	jsonRemove.paths = append(jsonRemove.paths, path)
+	jsonRemove.mutex.Unlock()
+	return jsonRemove
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (jsonRemove *JSONRemoveExpression) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		switch stmt.Dialector.Name() {
+		case "mysql", "sqlite":
+			builder.WriteString("JSON_REMOVE(")
+			builder.WriteQuoted(jsonRemove.column)
+			for _, path := range jsonRemove.paths {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, jsonUpdatePath(stmt, path))
+			}
+			builder.WriteString(")")
+
+		case "postgres":
+			builder.WriteQuoted(jsonRemove.column)
+			for _, path := range jsonRemove.paths {
+				builder.WriteString(" #- ")
+				builder.AddVar(stmt, jsonUpdatePath(stmt, path))
+			}
+
+		case "sqlserver":
+			// JSON_MODIFY deletes the key when the new value is NULL
+			for range jsonRemove.paths {
+				builder.WriteString("JSON_MODIFY(")
+			}
+			builder.WriteQuoted(jsonRemove.column)
+			for _, path := range jsonRemove.paths {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, jsonUpdatePath(stmt, path))
+				builder.WriteString(",NULL)")
+			}
+		}
+	}
+}
+
+// JSONArrayUpdateExpression json array update expression, implements clause.Expression interface to use as updater
+type JSONArrayUpdateExpression struct {
+	column  string
+	updates []jsonArrayUpdate
+	mutex   sync.RWMutex
+}
+
+type jsonArrayUpdate struct {
+	operation string
+	path      string
+	values    []interface{}
+}
+
+// JSONArrayUpdate update json arrays of json column in place, the array is the column itself when path is
+// empty or `$`, otherwise path is the same as JSONSetExpression.Set
+//
+//	DB.UpdateColumn("events", JSONArrayUpdate("events").Append("", event))
+//	DB.UpdateColumn("attr", JSONArrayUpdate("attr").Prepend("tags", "tag0").Remove("roles", "guest"))
+func JSONArrayUpdate(column string) *JSONArrayUpdateExpression {
+	return &JSONArrayUpdateExpression{column: column}
+}
+
+// Append add values to the end of the array at path
+func (jsonArray *JSONArrayUpdateExpression) Append(path string, values ...interface{}) *JSONArrayUpdateExpression {
+	return jsonArray.update("append", path, values)
+}
+
+// Prepend add values to the start of the array at path, this method is not supported by SQL Server
+func (jsonArray *JSONArrayUpdateExpression) Prepend(path string, values ...interface{}) *JSONArrayUpdateExpression {
+	return jsonArray.update("prepend", path, values)
+}
+
+// Remove remove elements equal to any of values from the array at path, elements are equal if both their json
+// type and value are equal, e.g. true doesn't remove 1, this method is not supported by SQL Server
+func (jsonArray *JSONArrayUpdateExpression) Remove(path string, values ...interface{}) *JSONArrayUpdateExpression {
+	return jsonArray.update("remove", path, values)
+}
+
+func (jsonArray *JSONArrayUpdateExpression) update(operation, path string, values []interface{}) *JSONArrayUpdateExpression {
+	jsonArray.mutex.Lock()
+	jsonArray.updates = append(jsonArray.updates, jsonArrayUpdate{operation: operation, path: path, values: values})
+	jsonArray.mutex.Unlock()
+	return jsonArray
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (jsonArray *JSONArrayUpdateExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	paths := make([]jsonPath, len(jsonArray.updates))
+	var readsTwice bool
+	for idx, update := range jsonArray.updates {
+		if paths[idx], ok = parseStmtJSONUpdatePath(stmt, update.path); !ok {
+			return
+		}
+		readsTwice = readsTwice || update.readsTwice(stmt.Dialector.Name(), paths[idx])
+	}
+
+	if !readsTwice {
+		jsonArray.build(stmt, paths, len(jsonArray.updates))
+		return
+	}
+
+	// updates that read the previous document twice would repeat the previous updates, so every document is
+	// bound once to d<n> of a lateral join, where d0 is the column
+	doc := "doc"
+	if stmt.Dialector.Name() == "sqlite" {
+		doc = "value"
+	}
+	stmt.WriteString(fmt.Sprintf("(SELECT d%d.%s FROM ", len(jsonArray.updates), doc))
+	for idx := 0; idx <= len(jsonArray.updates); idx++ {
+		write := func() { stmt.WriteQuoted(jsonArray.column) }
+		if idx > 0 {
+			stmt.WriteString(", ")
+			prev := fmt.Sprintf("d%d.%s", idx-1, doc)
+			write = func() {
+				jsonArray.buildUpdate(stmt, jsonArray.updates[idx-1], paths[idx-1], func() { stmt.WriteString(prev) })
+			}
+		}
+
+		switch stmt.Dialector.Name() {
+		case "mysql":
+			stmt.WriteString("JSON_TABLE(CONCAT('[',")
+			write()
+			stmt.WriteString(",']'),'$[*]' COLUMNS(doc JSON PATH '$'))")
+		case "sqlite":
+			stmt.WriteString("json_each(json_array(json(")
+			write()
+			stmt.WriteString(")))")
+		case "postgres":
+			if idx > 0 {
+				stmt.WriteString("LATERAL ")
+			}
+			stmt.WriteString("(SELECT ")
+			write()
+			stmt.WriteString(" AS doc)")
+		}
+		stmt.WriteString(fmt.Sprintf(" AS d%d", idx))
+	}
+	stmt.WriteByte(')')
+}
+
+// readsTwice reports if update reads the previous document twice in dialect
+func (update jsonArrayUpdate) readsTwice(dialect string, path jsonPath) bool {
+	switch dialect {
+	case "mysql":
+		return len(path) > 0 && update.operation == "remove"
+	case "sqlite":
+		return len(path) > 0 && update.operation != "append"
+	case "postgres":
+		return len(path) > 0
+	}
+	return false
+}
+
+// build writes the json document after applying the first n updates, each update reads the previous one once
+func (jsonArray *JSONArrayUpdateExpression) build(stmt *gorm.Statement, paths []jsonPath, n int) {
+	if n == 0 {
+		stmt.WriteQuoted(jsonArray.column)
+		return
+	}
+	jsonArray.buildUpdate(stmt, jsonArray.updates[n-1], paths[n-1], func() { jsonArray.build(stmt, paths, n-1) })
+}
+
+// buildUpdate writes the json document after applying update to the document written by prev
+func (jsonArray *JSONArrayUpdateExpression) buildUpdate(stmt *gorm.Statement, update jsonArrayUpdate, path jsonPath, prev func()) {
+	writeValues := func() {
+		for idx, value := range update.values {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			writeJSONValue(stmt, value)
+		}
+	}
+
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		switch update.operation {
+		case "append":
+			stmt.WriteString("JSON_ARRAY_APPEND(")
+			prev()
+			for _, value := range update.values {
+				stmt.WriteByte(',')
+				stmt.AddVar(stmt, path.sql("mysql"))
+				stmt.WriteByte(',')
+				writeJSONValue(stmt, value)
+			}
+			stmt.WriteByte(')')
+		case "prepend":
+			stmt.WriteString("JSON_ARRAY_INSERT(")
+			prev()
+			for idx, value := range update.values {
+				stmt.WriteByte(',')
+				stmt.AddVar(stmt, append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathIndex, index: idx}).sql("mysql"))
+				stmt.WriteByte(',')
+				writeJSONValue(stmt, value)
+			}
+			stmt.WriteByte(')')
+		case "remove":
+			if len(path) > 0 {
+				stmt.WriteString("JSON_SET(")
+				prev()
+				stmt.WriteByte(',')
+				stmt.AddVar(stmt, path.sql("mysql"))
+				stmt.WriteByte(',')
+			}
+			// JSON_ARRAYAGG of MySQL has no ORDER BY, the elements are aggregated in order as a window function
+			if v, ok := stmt.Dialector.(*mysql.Dialector); ok && strings.Contains(v.ServerVersion, "MariaDB") {
+				stmt.WriteString("COALESCE((SELECT JSON_ARRAYAGG(j.value ORDER BY j.idx) FROM JSON_TABLE(")
+			} else {
+				stmt.WriteString("COALESCE((SELECT JSON_ARRAYAGG(j.value) OVER (ORDER BY j.idx ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) FROM JSON_TABLE(")
+			}
+			prev()
+			stmt.WriteString("," + append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathAny}).literal())
+			stmt.WriteString(" COLUMNS(idx FOR ORDINALITY, value JSON PATH '$')) AS j")
+			// elements are compared with the json of each value, so 1, "1" and true are different values
+			if len(update.values) > 0 {
+				stmt.WriteString(" WHERE NOT COALESCE(")
+				for idx, value := range update.values {
+					if idx > 0 {
+						stmt.WriteString(" OR ")
+					}
+					stmt.WriteString("j.value = JSON_EXTRACT(JSON_ARRAY(")
+					writeJSONValue(stmt, value)
+					stmt.WriteString("),'$[0]')")
+				}
+				stmt.WriteString(",FALSE)")
+			}
+			stmt.WriteString(" LIMIT 1),JSON_ARRAY())")
+			if len(path) > 0 {
+				stmt.WriteByte(')')
+			}
+		}
+
+	case "sqlite":
+		if update.operation == "append" {
+			stmt.WriteString("json_insert(")
+			prev()
+			for _, value := range update.values {
+				stmt.WriteByte(',')
+				stmt.AddVar(stmt, path.sql("sqlite")+"[#]")
+				stmt.WriteByte(',')
+				writeJSONValue(stmt, value)
+			}
+			stmt.WriteByte(')')
+			break
+		}
+
+		// rebuild the array from the elements of json_each, which are converted back to json by their type
+		if len(path) > 0 {
+			stmt.WriteString("json_set(")
+			prev()
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, path.sql("sqlite"))
+			stmt.WriteByte(',')
+		}
+		stmt.WriteString("json((SELECT json_group_array(CASE WHEN type IN ('object','array') THEN json(value) WHEN type IN ('true','false') THEN json(type) ELSE value END) FROM ")
+		if update.operation == "prepend" {
+			stmt.WriteString("(SELECT 0 AS o, key, value, type FROM json_each(json_array(")
+			writeValues()
+			stmt.WriteString(")) UNION ALL SELECT 1, key, value, type FROM json_each(")
+			prev()
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, path.sql("sqlite"))
+			stmt.WriteString(") ORDER BY 1, 2)")
+		} else {
+			stmt.WriteString("json_each(")
+			prev()
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, path.sql("sqlite"))
+			// elements are compared by their type and value, so 1, "1" and true are different and nil removes nulls
+			stmt.WriteString(") AS e WHERE NOT EXISTS(SELECT 1 FROM json_each(json_array(")
+			for idx, value := range update.values {
+				if idx > 0 {
+					stmt.WriteByte(',')
+				}
+				// booleans are bound as integers, which would remove the numbers 1 and 0
+				if jsonKindOf(value) == jsonKindBool {
+					stmt.WriteString("json('" + strconv.FormatBool(reflect.Indirect(reflect.ValueOf(value)).Bool()) + "')")
+				} else {
+					writeJSONValue(stmt, value)
+				}
+			}
+			stmt.WriteString(")) AS v WHERE (v.type = e.type OR v.type IN ('integer','real') AND e.type IN ('integer','real')) AND v.value IS e.value)")
+		}
+		stmt.WriteString("))")
+		if len(path) > 0 {
+			stmt.WriteByte(')')
+		}
+
+	case "postgres":
+		// missing arrays are created, as jsonb_set returns NULL for a NULL value
+		writeArray := func() {
+			stmt.WriteString("COALESCE(")
+			prev()
+			if len(path) > 0 {
+				stmt.WriteString(" #> ")
+				stmt.AddVar(stmt, path.array())
+			}
+			stmt.WriteString(",'[]')")
+		}
 
-					if _, ok := jsonQuery.equalsValue.(string); ok {
-						stmt.AddVar(builder, jsonQuery.equalsValue)
-					} else {
-						stmt.AddVar(builder, fmt.Sprint(jsonQuery.equalsValue))
-					}
-				}
-			case jsonQuery.likes:
-				if len(jsonQuery.keys) > 0 {
-					builder.WriteString(fmt.Sprintf("json_extract_path_text(%v::json,", stmt.Quote(jsonQuery.column)))
+		if len(path) > 0 {
+			stmt.WriteString("jsonb_set(")
+			prev()
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, path.array())
+			stmt.WriteByte(',')
+		}
+		switch update.operation {
+		case "append":
+			stmt.WriteByte('(')
+			writeArray()
+			stmt.WriteString(" || jsonb_build_array(")
+			writeValues()
+			stmt.WriteString("))")
+		case "prepend":
+			stmt.WriteString("(jsonb_build_array(")
+			writeValues()
+			stmt.WriteString(") || ")
+			writeArray()
+			stmt.WriteByte(')')
+		case "remove":
+			stmt.WriteString("COALESCE((SELECT jsonb_agg(a.value ORDER BY a.ordinality) FROM jsonb_array_elements(")
+			writeArray()
+			stmt.WriteString(") WITH ORDINALITY AS a WHERE a.value NOT IN (SELECT jsonb_array_elements(jsonb_build_array(")
+			writeValues()
+			stmt.WriteString(")))),'[]')")
+		}
+		if len(path) > 0 {
+			stmt.WriteByte(')')
+		}
 
-					for idx, key := range jsonQuery.keys {
-						if idx > 0 {
-							builder.WriteByte(',')
-						}
-						stmt.AddVar(builder, key)
-					}
-					builder.WriteString(") LIKE ")
+	case "sqlserver":
+		if update.operation != "append" {
+			_ = stmt.AddError(fmt.Errorf("json array %s is not supported by sqlserver", update.operation))
+			return
+		}
 
-					if _, ok := jsonQuery.equalsValue.(string); ok {
-						stmt.AddVar(builder, jsonQuery.equalsValue)
-					} else {
-						stmt.AddVar(builder, fmt.Sprint(jsonQuery.equalsValue))
-					}
-				}
-			}
+		for range update.values {
+			stmt.WriteString("JSON_MODIFY(")
+		}
+		prev()
+		for _, value := range update.values {
+			stmt.WriteByte(',')
+			stmt.AddVar(stmt, "append "+path.sql("sqlserver"))
+			stmt.WriteByte(',')
+			writeJSONValue(stmt, value)
+			stmt.WriteByte(')')
 		}
 	}
 }
 
-// JSONOverlapsExpression JSON_OVERLAPS expression, implements clause.Expression interface to use as querier
-type JSONOverlapsExpression struct {
-	column clause.Expression
-	val    string
+// JSONMergePatchExpression json merge patch expression, implements clause.Expression interface to use as updater
+type JSONMergePatchExpression struct {
+	column string
+	patch  interface{}
 }
 
-// JSONOverlaps query column as json
-func JSONOverlaps(column clause.Expression, value string) *JSONOverlapsExpression {
-	return &JSONOverlapsExpression{
-		column: column,
-		val:    value,
-	}
+// JSONMergePatch update json column with a RFC 7396 merge patch, fields of patch replace fields of the
+// column, nested objects are merged recursively and null fields are removed. patch can be json text
+// like JSON, string and []byte, or any value that is marshaled to json
+//
+//	DB.UpdateColumn("attr", JSONMergePatch("attr", map[string]interface{}{"age": 20, "orgs": map[string]interface{}{"orga": nil}}))
+func JSONMergePatch(column string, patch interface{}) *JSONMergePatchExpression {
+	return &JSONMergePatchExpression{column: column, patch: patch}
 }
 
 // Build implements clause.Expression
-// only mysql support JSON_OVERLAPS
-func (json *JSONOverlapsExpression) Build(builder clause.Builder) {
-	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
-		case "mysql":
-			builder.WriteString("JSON_OVERLAPS(")
-			json.column.Build(builder)
-			builder.WriteString(",")
-			builder.AddVar(stmt, json.val)
-			builder.WriteString(")")
-		}
+// support mysql, sqlite and postgres
+func (jsonPatch *JSONMergePatchExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
 	}
-}
 
-type columnExpression string
+	var (
+		patch []byte
+		err   error
+	)
+	switch v := jsonPatch.patch.(type) {
+	case string:
+		patch = []byte(v)
+	case []byte:
+		patch = v
+	default:
+		patch, err = json.Marshal(v)
+	}
+	if err == nil && !json.Valid(patch) {
+		err = fmt.Errorf("invalid json merge patch: %s", patch)
+	}
+	if err != nil {
+		_ = stmt.AddError(err)
+		return
+	}
 
-func Column(col string) columnExpression {
-	return columnExpression(col)
-}
+	switch stmt.Dialector.Name() {
+	case "mysql", "sqlite":
+		// a NULL column is patched like an empty object
+		if stmt.Dialector.Name() == "mysql" {
+			builder.WriteString("JSON_MERGE_PATCH(COALESCE(")
+		} else {
+			builder.WriteString("json_patch(COALESCE(")
+		}
+		builder.WriteQuoted(jsonPatch.column)
+		builder.WriteString(",'{}'),")
+		builder.AddVar(stmt, string(patch))
+		builder.WriteByte(')')
 
-func (col columnExpression) Build(builder clause.Builder) {
-	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
-		case "mysql", "sqlite", "postgres":
-			builder.WriteString(stmt.Quote(string(col)))
+	case "postgres":
+		// || only merges the top level, so the patch is expanded into nested expressions
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(string(patch)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			_ = stmt.AddError(err)
+			return
+		}
+
+		if object, ok := value.(map[string]interface{}); ok {
+			writePostgresMergePatch(stmt, func() { stmt.WriteQuoted(jsonPatch.column) }, object)
+		} else {
+			builder.AddVar(stmt, string(patch))
+			builder.WriteString("::jsonb")
 		}
+
+	default:
+		_ = stmt.AddError(fmt.Errorf("json merge patch is not supported by %s", stmt.Dialector.Name()))
 	}
 }
 
-const prefix = "$."
+// writePostgresMergePatch writes the merge of the object patch into the jsonb written by target
+func writePostgresMergePatch(stmt *gorm.Statement, target func(), patch map[string]interface{}) {
+	var (
+		keys    = make([]string, 0, len(patch))
+		removes jsonPath
+		objects []string
+		values  = map[string]interface{}{}
+	)
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-func jsonQueryJoin(keys []string) string {
-	if len(keys) == 1 {
-		return prefix + keys[0]
+	for _, key := range keys {
+		switch patch[key].(type) {
+		case nil:
+			removes = append(removes, jsonPathSegment{kind: jsonPathKey, key: key})
+		case map[string]interface{}:
+			objects = append(objects, key)
+		default:
+			values[key] = patch[key]
+		}
 	}
 
-	n := len(prefix)
-	n += len(keys) - 1
-	for i := 0; i < len(keys); i++ {
-		n += len(keys[i])
+	// the target is replaced by an empty object if it is not an object
+	stmt.WriteString("(CASE WHEN jsonb_typeof(")
+	target()
+	stmt.WriteString(") = 'object' THEN ")
+	target()
+	stmt.WriteString(" ELSE '{}' END")
+	if len(removes) > 0 {
+		stmt.WriteString(" - ")
+		stmt.AddVar(stmt, removes.array())
+		stmt.WriteString("::text[]")
 	}
+	stmt.WriteByte(')')
+
+	if len(objects) > 0 {
+		stmt.WriteString(" || jsonb_build_object(")
+		for idx, key := range objects {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			stmt.AddVar(stmt, key)
+			stmt.WriteString("::text,")
 
-	var b strings.Builder
-	b.Grow(n)
-	b.WriteString(prefix)
-	b.WriteString(keys[0])
-	for _, key := range keys[1:] {
-		b.WriteString(".")
-		b.WriteString(key)
+			key := key
+			writePostgresMergePatch(stmt, func() {
+				target()
+				stmt.WriteString(" -> ")
+				stmt.AddVar(stmt, key)
+				stmt.WriteString("::text")
+			}, patch[key].(map[string]interface{}))
+		}
+		stmt.WriteByte(')')
 	}
-	return b.String()
-}
 
-// JSONSetExpression json set expression, implements clause.Expression interface to use as updater
-type JSONSetExpression struct {
-	column     string
-	path2value map[string]interface{}
-	mutex      sync.RWMutex
+	if len(values) > 0 {
+		b, _ := json.Marshal(values)
+		stmt.WriteString(" || ")
+		stmt.AddVar(stmt, string(b))
+		stmt.WriteString("::jsonb")
+	}
 }
 
-// JSONSet update fields of json column
-func JSONSet(column string) *JSONSetExpression {
-	return &JSONSetExpression{column: column, path2value: make(map[string]interface{})}
+// JSONIncrementExpression json increment expression, implements clause.Expression interface to use as updater
+type JSONIncrementExpression struct {
+	column string
+	paths  []string
+	deltas []interface{}
+	mutex  sync.RWMutex
 }
 
-// Set return clause.Expression.
-//
-//	{
-//		"age": 20,
-//		"name": "json-1",
-//		"orgs": {"orga": "orgv"},
-//		"tags": ["tag1", "tag2"]
-//	}
-//
-//	// In MySQL/SQLite, path is `age`, `name`, `orgs.orga`, `tags[0]`, `tags[1]`.
-//	DB.UpdateColumn("attr", JSONSet("attr").Set("orgs.orga", 42))
+// JSONIncrement increment numbers of json column in place, missing numbers are created with the value of delta,
+// missing parent objects are created too, values that are not numbers are replaced like missing numbers
 //
-//	// In PostgreSQL, path is `{age}`, `{name}`, `{orgs,orga}`, `{tags, 0}`, `{tags, 1}`.
-//	DB.UpdateColumn("attr", JSONSet("attr").Set("{orgs, orga}", "bar"))
-func (jsonSet *JSONSetExpression) Set(path string, value interface{}) *JSONSetExpression {
-	jsonSet.mutex.Lock()
-	jsonSet.path2value[path] = value
-	jsonSet.mutex.Unlock()
-	return jsonSet
+//	DB.UpdateColumn("stats", JSONIncrement("stats").Add("views", 1).Add("rating", -0.5))
+func JSONIncrement(column string) *JSONIncrementExpression {
+	return &JSONIncrementExpression{column: column}
 }
 
-// Build implements clause.Expression
-// support mysql, sqlite and postgres
-func (jsonSet *JSONSetExpression) Build(builder clause.Builder) {
-	if stmt, ok := builder.(*gorm.Statement); ok {
-		switch stmt.Dialector.Name() {
-		case "mysql":
+// Add add delta to the number at path, delta must be an integer or a float, path is the same as
+// JSONSetExpression.Set, deltas added to the same path are summed up
+func (jsonIncr *JSONIncrementExpression) Add(path string, delta interface{}) *JSONIncrementExpression {
+	jsonIncr.mutex.Lock()
+	jsonIncr.paths = append(jsonIncr.paths, path)
+	jsonIncr.deltas = append(jsonIncr.deltas, delta)
+	jsonIncr.mutex.Unlock()
+	return jsonIncr
+}
 
-			var isMariaDB bool
-			if v, ok := stmt.Dialector.(*mysql.Dialector); ok {
-				isMariaDB = strings.Contains(v.ServerVersion, "MariaDB")
-			}
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (jsonIncr *JSONIncrementExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
 
-			builder.WriteString("JSON_SET(")
-			builder.WriteQuoted(jsonSet.column)
-			for path, value := range jsonSet.path2value {
-				builder.WriteByte(',')
-				builder.AddVar(stmt, prefix+path)
-				builder.WriteByte(',')
+	// missing parent objects of the paths are created before setting the numbers, deltas of the same path are
+	// added together, as every number is read from the column
+	var (
+		paths, parents []string
+		jsonPaths      []jsonPath
+		deltas         []interface{}
+		integers       []bool
+		seen           = map[string]bool{}
+		positions      = map[string]int{}
+	)
+	for idx, str := range jsonIncr.paths {
+		var integer bool
+		delta := reflect.Indirect(reflect.ValueOf(jsonIncr.deltas[idx]))
+		switch delta.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			integer = true
+		case reflect.Float32, reflect.Float64:
+		default:
+			_ = stmt.AddError(fmt.Errorf("invalid json increment delta %v for %s, should be an integer or a float", jsonIncr.deltas[idx], str))
+			return
+		}
 
-				if _, ok := value.(clause.Expression); ok {
-					stmt.AddVar(builder, value)
-					continue
-				}
+		path, ok := parseStmtJSONUpdatePath(stmt, str)
+		if !ok {
+			return
+		} else if len(path) == 0 {
+			_ = stmt.AddError(fmt.Errorf("invalid json increment path %q, should not be the column itself", str))
+			return
+		}
 
-				rv := reflect.ValueOf(value)
-				if rv.Kind() == reflect.Ptr {
-					rv = rv.Elem()
+		for idx := 1; idx < len(path); idx++ {
+			if path[idx].kind == jsonPathKey {
+				parent := path[:idx].sql(stmt.Dialector.Name())
+				if stmt.Dialector.Name() == "postgres" {
+					parent = path[:idx].array()
 				}
-				switch rv.Kind() {
-				case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
-					b, _ := json.Marshal(value)
-					if isMariaDB {
-						stmt.AddVar(builder, string(b))
-						break
-					}
-					stmt.AddVar(builder, gorm.Expr("CAST(? AS JSON)", string(b)))
-				case reflect.Bool:
-					builder.WriteString(strconv.FormatBool(rv.Bool()))
-				default:
-					stmt.AddVar(builder, value)
+				if !seen[parent] {
+					seen[parent] = true
+					parents = append(parents, parent)
 				}
 			}
-			builder.WriteString(")")
+		}
 
-		case "sqlite":
-			builder.WriteString("JSON_SET(")
-			builder.WriteQuoted(jsonSet.column)
-			for path, value := range jsonSet.path2value {
-				builder.WriteByte(',')
-				builder.AddVar(stmt, prefix+path)
-				builder.WriteByte(',')
+		rendered := path.sql(stmt.Dialector.Name())
+		if stmt.Dialector.Name() == "postgres" {
+			rendered = path.array()
+		}
+		if pos, ok := positions[rendered]; ok {
+			deltas[pos] = addJSONIncrementDelta(deltas[pos], delta.Interface())
+			integers[pos] = integers[pos] && integer
+			continue
+		}
+		positions[rendered] = len(paths)
+		paths = append(paths, rendered)
+		jsonPaths = append(jsonPaths, path)
+		deltas = append(deltas, delta.Interface())
+		integers = append(integers, integer)
+	}
 
-				if _, ok := value.(clause.Expression); ok {
-					stmt.AddVar(builder, value)
-					continue
-				}
+	// the numbers are read from the column, a NULL column is updated like an empty object
+	src := jsonSource{column: jsonIncr.column}
+	switch stmt.Dialector.Name() {
+	case "mysql", "sqlite":
+		builder.WriteString("JSON_SET(")
+		if len(parents) > 0 {
+			builder.WriteString("JSON_INSERT(")
+		}
+		builder.WriteString("COALESCE(")
+		builder.WriteQuoted(jsonIncr.column)
+		builder.WriteString(",'{}')")
+		if len(parents) > 0 {
+			for _, parent := range parents {
+				builder.WriteByte(',')
+				builder.AddVar(stmt, parent)
+				builder.WriteString(",JSON_OBJECT()")
+			}
+			builder.WriteByte(')')
+		}
 
-				rv := reflect.ValueOf(value)
-				if rv.Kind() == reflect.Ptr {
-					rv = rv.Elem()
-				}
-				switch rv.Kind() {
-				case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
-					b, _ := json.Marshal(value)
-					stmt.AddVar(builder, gorm.Expr("JSON(?)", string(b)))
-				default:
-					stmt.AddVar(builder, value)
-				}
+		for idx, path := range paths {
+			builder.WriteByte(',')
+			builder.AddVar(stmt, path)
+			builder.WriteByte(',')
+			if stmt.Dialector.Name() == "sqlite" {
+				builder.WriteString("COALESCE(")
+				writeJSONTypedValue(stmt, src, jsonPaths[idx], jsonKindNumber)
+				builder.WriteString(",0) + ")
+				builder.AddVar(stmt, deltas[idx])
+				continue
 			}
-			builder.WriteString(")")
 
-		case "postgres":
-			var expr clause.Expression = columnExpression(jsonSet.column)
-			for path, value := range jsonSet.path2value {
-				if _, ok = value.(clause.Expression); ok {
-					expr = gorm.Expr("JSONB_SET(?,?,?)", expr, path, value)
-					continue
+			// arithmetic on json values results in doubles, so integers are added to integers as integers, the
+			// value is extracted once into n.num of a json table to choose the sum by its json type, the sums are
+			// cast to json to keep their types, which MariaDB keeps without casts
+			writeSum := func(sum string) {
+				if v, ok := stmt.Dialector.(*mysql.Dialector); ok && strings.Contains(v.ServerVersion, "MariaDB") {
+					builder.WriteString(sum)
+					builder.AddVar(stmt, deltas[idx])
 				} else {
-					b, _ := json.Marshal(value)
-					expr = gorm.Expr("JSONB_SET(?,?,?)", expr, path, string(b))
+					builder.WriteString("CAST(" + sum)
+					builder.AddVar(stmt, deltas[idx])
+					builder.WriteString(" AS JSON)")
 				}
 			}
-			stmt.AddVar(builder, expr)
+			builder.WriteString("(SELECT CASE")
+			if integers[idx] {
+				builder.WriteString(" WHEN JSON_TYPE(n.num) IN ('INTEGER','UNSIGNED INTEGER') THEN ")
+				writeSum("CAST(n.num AS SIGNED) + ")
+			}
+			builder.WriteString(" WHEN JSON_TYPE(n.num) IN ('INTEGER','UNSIGNED INTEGER','DOUBLE','DECIMAL') THEN ")
+			writeSum("n.num + ")
+			builder.WriteString(" ELSE ")
+			writeSum("")
+			builder.WriteString(" END FROM JSON_TABLE(CONCAT('[',COALESCE(JSON_EXTRACT(")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteByte(',')
+			builder.AddVar(stmt, path)
+			builder.WriteString("),'null'),']'),'$[*]' COLUMNS(num JSON PATH '$')) AS n)")
+		}
+		builder.WriteByte(')')
+
+	case "postgres":
+		for range append(parents, paths...) {
+			builder.WriteString("jsonb_set(")
+		}
+		builder.WriteString("COALESCE(")
+		builder.WriteQuoted(jsonIncr.column)
+		builder.WriteString(",'{}')")
+		for _, parent := range parents {
+			builder.WriteByte(',')
+			builder.AddVar(stmt, parent)
+			builder.WriteString(",COALESCE(")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteString(" #> ")
+			builder.AddVar(stmt, parent)
+			builder.WriteString(",'{}'))")
+		}
+		for idx, path := range paths {
+			builder.WriteByte(',')
+			builder.AddVar(stmt, path)
+			builder.WriteString(",to_jsonb(COALESCE(CASE WHEN jsonb_typeof(")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteString(" #> ")
+			builder.AddVar(stmt, path)
+			builder.WriteString(") = 'number' THEN (")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteString(" #>> ")
+			builder.AddVar(stmt, path)
+			builder.WriteString(")::numeric END,0) + ")
+			builder.AddVar(stmt, deltas[idx])
+			builder.WriteString("))")
+		}
+
+	case "sqlserver":
+		// the number is added as a float if it is not an integer, the json document is bound once to choose
+		// between the two sums as their types are different, the number is read once by its key from OPENJSON
+		// of its parent, which tells numbers apart from strings
+		writeNumber := func(idx int) {
+			builder.WriteString("(SELECT [value] FROM OPENJSON(")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteByte(',')
+			builder.AddVar(stmt, jsonPaths[idx][:len(jsonPaths[idx])-1].sql("sqlserver"))
+			builder.WriteString(") WHERE [key] = ")
+			builder.AddVar(stmt, jsonPaths[idx][len(jsonPaths[idx])-1:].keys()[0])
+			builder.WriteString(" AND [type] = 2)")
+		}
+		for idx := len(paths) - 1; idx >= 0; idx-- {
+			if !integers[idx] {
+				builder.WriteString("JSON_MODIFY(")
+				continue
+			}
+			builder.WriteString("(SELECT CASE WHEN TRY_CAST(n.num AS BIGINT) IS NOT NULL THEN JSON_MODIFY(d.doc,")
+			builder.AddVar(stmt, paths[idx])
+			builder.WriteString(",CAST(n.num AS BIGINT) + ")
+			builder.AddVar(stmt, deltas[idx])
+			builder.WriteString(") WHEN n.num IS NOT NULL THEN JSON_MODIFY(d.doc,")
+			builder.AddVar(stmt, paths[idx])
+			builder.WriteString(",CAST(n.num AS FLOAT) + ")
+			builder.AddVar(stmt, deltas[idx])
+			builder.WriteString(") ELSE JSON_MODIFY(d.doc,")
+			builder.AddVar(stmt, paths[idx])
+			builder.WriteByte(',')
+			builder.AddVar(stmt, deltas[idx])
+			builder.WriteString(") END FROM (VALUES (")
+		}
+		for range parents {
+			builder.WriteString("JSON_MODIFY(")
 		}
+		builder.WriteString("COALESCE(")
+		builder.WriteQuoted(jsonIncr.column)
+		builder.WriteString(",'{}')")
+		for _, parent := range parents {
+			builder.WriteByte(',')
+			builder.AddVar(stmt, parent)
+			builder.WriteString(",JSON_QUERY(COALESCE(JSON_QUERY(")
+			builder.WriteQuoted(jsonIncr.column)
+			builder.WriteByte(',')
+			builder.AddVar(stmt, parent)
+			builder.WriteString("),'{}')))")
+		}
+		for idx, path := range paths {
+			if integers[idx] {
+				builder.WriteString(")) AS d(doc) CROSS APPLY (SELECT ")
+				writeNumber(idx)
+				builder.WriteString(" AS num) AS n)")
+				continue
+			}
+			builder.WriteByte(',')
+			builder.AddVar(stmt, path)
+			builder.WriteString(",COALESCE(TRY_CAST(")
+			writeNumber(idx)
+			builder.WriteString(" AS FLOAT),0) + ")
+			builder.AddVar(stmt, deltas[idx])
+			builder.WriteByte(')')
+		}
+	}
+}
+
+// addJSONIncrementDelta adds the deltas of the same path, the sum is a float if any of them is a float
+func addJSONIncrementDelta(x, y interface{}) interface{} {
+	number := func(v interface{}) (int64, float64, bool) {
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int(), float64(rv.Int()), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int64(rv.Uint()), float64(rv.Uint()), true
+		}
+		return 0, rv.Float(), false
+	}
+
+	xi, xf, xInteger := number(x)
+	yi, yf, yInteger := number(y)
+	if xInteger && yInteger {
+		return xi + yi
 	}
+	return xf + yf
 }
 
 func JSONArrayQuery(column string) *JSONArrayExpression {
@@ -458,12 +2626,14 @@ func JSONArrayQuery(column string) *JSONArrayExpression {
 type JSONArrayExpression struct {
 	contains    bool
 	in          bool
+	containsAll bool
+	containsAny bool
 	column      string
 	keys        []string
 	equalsValue interface{}
 }
 
-// Contains checks if column[keys] contains the value given. The keys parameter is only supported for MySQL and SQLite.
+// Contains checks if the array at column[keys] contains the value given
 func (json *JSONArrayExpression) Contains(value interface{}, keys ...string) *JSONArrayExpression {
 	json.contains = true
 	json.equalsValue = value
@@ -471,7 +2641,7 @@ func (json *JSONArrayExpression) Contains(value interface{}, keys ...string) *JS
 	return json
 }
 
-// In checks if columns[keys] is in the array value given. This method is only supported for MySQL and SQLite.
+// In checks if columns[keys] is in the array value given, an array matches if all of its elements are in value
 func (json *JSONArrayExpression) In(value interface{}, keys ...string) *JSONArrayExpression {
 	json.in = true
 	json.keys = keys
@@ -479,11 +2649,38 @@ func (json *JSONArrayExpression) In(value interface{}, keys ...string) *JSONArra
 	return json
 }
 
+// ContainsAll checks if the array at column[keys] contains all elements of the array values given,
+// every array contains empty values
+func (json *JSONArrayExpression) ContainsAll(values interface{}, keys ...string) *JSONArrayExpression {
+	json.containsAll = true
+	json.keys = keys
+	json.equalsValue = values
+	return json
+}
+
+// ContainsAny checks if the array at column[keys] contains any element of the array values given
+func (json *JSONArrayExpression) ContainsAny(values interface{}, keys ...string) *JSONArrayExpression {
+	json.containsAny = true
+	json.keys = keys
+	json.equalsValue = values
+	return json
+}
+
+// Length returns the number of elements of the array at column[keys], NULL if the value is not an array
+func (json *JSONArrayExpression) Length(keys ...string) *JSONExtractExpression {
+	return &JSONExtractExpression{column: json.column, path: parseJSONKeys(keys).sql("mysql"), kind: "length"}
+}
+
 // Build implements clause.Expression
 func (json *JSONArrayExpression) Build(builder clause.Builder) {
 	if stmt, ok := builder.(*gorm.Statement); ok {
 		switch stmt.Dialector.Name() {
 		case "mysql":
+			if (json.contains || json.containsAll || json.containsAny) && supportsMySQLMemberOf(stmt) {
+				json.buildMemberOf(stmt)
+				return
+			}
+
 			switch {
 			case json.contains:
 				builder.WriteString("JSON_CONTAINS(" + stmt.Quote(json.column) + ",JSON_ARRAY(")
@@ -491,7 +2688,7 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteByte(')')
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 				}
 				builder.WriteByte(')')
 			case json.in:
@@ -504,7 +2701,35 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
+					builder.WriteByte(')')
+				}
+				builder.WriteByte(')')
+			case json.containsAll:
+				builder.WriteString("JSON_CONTAINS(" + stmt.Quote(json.column) + ",")
+				writeMySQLJSONArray(stmt, jsonArrayValues(json.equalsValue))
+				if len(json.keys) > 0 {
+					builder.WriteByte(',')
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
+				}
+				builder.WriteByte(')')
+			case json.containsAny:
+				builder.WriteByte('(')
+				values := jsonArrayValues(json.equalsValue)
+				if len(values) == 0 {
+					builder.WriteString("1 <> 1")
+				}
+				for idx, value := range values {
+					if idx > 0 {
+						builder.WriteString(" OR ")
+					}
+					builder.WriteString("JSON_CONTAINS(" + stmt.Quote(json.column) + ",JSON_ARRAY(")
+					builder.AddVar(stmt, value)
+					builder.WriteByte(')')
+					if len(json.keys) > 0 {
+						builder.WriteByte(',')
+						builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
+					}
 					builder.WriteByte(')')
 				}
 				builder.WriteByte(')')
@@ -516,7 +2741,7 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 				}
 				builder.WriteString(") WHERE value = ")
 				builder.AddVar(stmt, json.equalsValue)
@@ -524,7 +2749,7 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 				}
 				builder.WriteString(") > 0")
 			case json.in:
@@ -532,13 +2757,13 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 				}
 				builder.WriteString(") = 'array' THEN NOT EXISTS(SELECT 1 FROM json_each(")
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 				}
 				builder.WriteString(") WHERE value NOT IN ")
 				builder.AddVar(stmt, json.equalsValue)
@@ -549,20 +2774,335 @@ func (json *JSONArrayExpression) Build(builder clause.Builder) {
 				builder.WriteQuoted(json.column)
 				if len(json.keys) > 0 {
 					builder.WriteByte(',')
-					builder.AddVar(stmt, jsonQueryJoin(json.keys))
+					builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
 					builder.WriteByte(')')
 				}
 				builder.WriteString(" IN ")
 				builder.AddVar(stmt, json.equalsValue)
 				builder.WriteString(" END")
+			case json.containsAll, json.containsAny:
+				builder.WriteString("(json_type(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()))
+				builder.WriteString(") = 'array' AND ")
+				writeJSONArrayMatches(stmt, json.column, parseJSONKeys(json.keys).sql(stmt.Dialector.Name()), json.containsAll, json.equalsValue)
+				builder.WriteByte(')')
 			}
 		case "postgres":
+			src, path := jsonSource{column: json.column}, parseJSONKeys(json.keys)
+			switch {
+			case json.contains:
+				writeJSONBPath(stmt, src, path)
+				builder.WriteString(" @> ")
+				builder.AddVar(stmt, jsonArrayCandidate(stmt, []interface{}{json.equalsValue}))
+				builder.WriteString("::jsonb")
+			case json.containsAll:
+				writeJSONBPath(stmt, src, path)
+				builder.WriteString(" @> ")
+				builder.AddVar(stmt, jsonArrayCandidate(stmt, jsonArrayValues(json.equalsValue)))
+				builder.WriteString("::jsonb")
+			case json.containsAny:
+				builder.WriteByte('(')
+				values := jsonArrayValues(json.equalsValue)
+				if len(values) == 0 {
+					builder.WriteString("1 <> 1")
+				}
+				for idx, value := range values {
+					if idx > 0 {
+						builder.WriteString(" OR ")
+					}
+					writeJSONBPath(stmt, src, path)
+					builder.WriteString(" @> ")
+					builder.AddVar(stmt, jsonArrayCandidate(stmt, []interface{}{value}))
+					builder.WriteString("::jsonb")
+				}
+				builder.WriteByte(')')
+			case json.in:
+				// scalars are wrapped into an array, so they match if they are one of the values
+				builder.AddVar(stmt, jsonArrayCandidate(stmt, jsonArrayValues(json.equalsValue)))
+				builder.WriteString("::jsonb @> CASE WHEN jsonb_typeof(")
+				writeJSONBPath(stmt, src, path)
+				builder.WriteString(") = 'array' THEN ")
+				writeJSONBPath(stmt, src, path)
+				builder.WriteString(" ELSE jsonb_build_array(")
+				writeJSONBPath(stmt, src, path)
+				builder.WriteString(") END")
+			}
+		case "sqlserver":
+			path := parseJSONKeys(json.keys).sql("sqlserver")
+
 			switch {
 			case json.contains:
-				builder.WriteString(stmt.Quote(json.column))
-				builder.WriteString(" ? ")
+				writeOPENJSONContains(stmt, json.column, path, json.equalsValue)
+				builder.WriteString(" AND LEFT(LTRIM(JSON_QUERY(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(")),1) = '['")
+			case json.in:
+				builder.WriteString("((LEFT(LTRIM(JSON_QUERY(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(")),1) = '[' AND NOT EXISTS(SELECT 1 FROM OPENJSON(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") WHERE [value] NOT IN ")
+				builder.AddVar(stmt, json.equalsValue)
+				builder.WriteString(")) OR JSON_VALUE(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(") IN ")
 				builder.AddVar(stmt, json.equalsValue)
+				builder.WriteByte(')')
+			case json.containsAll, json.containsAny:
+				builder.WriteString("(LEFT(LTRIM(JSON_QUERY(")
+				builder.WriteQuoted(json.column)
+				builder.WriteByte(',')
+				builder.AddVar(stmt, path)
+				builder.WriteString(")),1) = '[' AND (")
+				values := jsonArrayValues(json.equalsValue)
+				if len(values) == 0 {
+					if json.containsAll {
+						builder.WriteString("1 = 1")
+					} else {
+						builder.WriteString("1 <> 1")
+					}
+				}
+				for idx, value := range values {
+					if idx > 0 && json.containsAll {
+						builder.WriteString(" AND ")
+					} else if idx > 0 {
+						builder.WriteString(" OR ")
+					}
+					writeOPENJSONContains(stmt, json.column, path, value)
+				}
+				builder.WriteString("))")
+			}
+		}
+	}
+}
+
+// writeOPENJSONContains writes the condition that the array at path contains value, elements are compared by
+// their json type, as comparing the text of mixed arrays with numbers fails to convert the text
+func writeOPENJSONContains(stmt *gorm.Statement, column string, path string, value interface{}) {
+	stmt.WriteString("EXISTS(SELECT 1 FROM OPENJSON(")
+	stmt.WriteQuoted(column)
+	stmt.WriteByte(',')
+	stmt.AddVar(stmt, path)
+	switch kind := jsonKindOf(value); kind {
+	case jsonKindNumber:
+		stmt.WriteString(") WHERE [type] = 2 AND TRY_CAST([value] AS FLOAT) = ")
+		writeJSONTypedVar(stmt, kind, value)
+	case jsonKindBool:
+		stmt.WriteString(") WHERE [type] = 3 AND [value] = ")
+		writeJSONTypedVar(stmt, kind, value)
+	case jsonKindTime:
+		stmt.WriteString(") WHERE [type] = 1 AND TRY_CAST([value] AS DATETIMEOFFSET) = ")
+		writeJSONTypedVar(stmt, kind, value)
+	default:
+		stmt.WriteString(") WHERE [type] = 1 AND [value] = CAST(")
+		writeJSONTypedVar(stmt, kind, value)
+		stmt.WriteString(" AS NVARCHAR(MAX))")
+	}
+	stmt.WriteByte(')')
+}
+
+// buildMemberOf writes the conditions of MySQL 8.0.17+ with MEMBER OF, JSON_CONTAINS and JSON_OVERLAPS on
+// column->'$.path', which can use multi-valued indexes of the array, like the indexes of the jsonindex tag
+func (json *JSONArrayExpression) buildMemberOf(stmt *gorm.Statement) {
+	array := func() {
+		stmt.WriteQuoted(json.column)
+		stmt.WriteString("->" + parseJSONKeys(json.keys).literal())
+	}
+
+	// only arrays contain values, MEMBER OF and JSON_OVERLAPS treat other values as arrays of themselves
+	stmt.WriteString("(JSON_TYPE(")
+	array()
+	stmt.WriteString(") = 'ARRAY' AND ")
+	switch {
+	case json.contains:
+		stmt.AddVar(stmt, json.equalsValue)
+		stmt.WriteString(" MEMBER OF(")
+		array()
+		stmt.WriteByte(')')
+	case json.containsAll:
+		stmt.WriteString("JSON_CONTAINS(")
+		array()
+		stmt.WriteByte(',')
+		writeMySQLJSONArray(stmt, jsonArrayValues(json.equalsValue))
+		stmt.WriteByte(')')
+	case json.containsAny:
+		if values := jsonArrayValues(json.equalsValue); len(values) == 0 {
+			stmt.WriteString("1 <> 1")
+		} else {
+			stmt.WriteString("JSON_OVERLAPS(")
+			array()
+			stmt.WriteString(",JSON_ARRAY")
+			stmt.AddVar(stmt, values)
+			stmt.WriteByte(')')
+		}
+	}
+	stmt.WriteByte(')')
+}
+
+// supportsMySQLMemberOf reports if the server supports MEMBER OF and JSON_OVERLAPS, which are added by MySQL 8.0.17,
+// MariaDB and servers of unknown versions fall back to JSON_CONTAINS
+func supportsMySQLMemberOf(stmt *gorm.Statement) bool {
+	v, ok := stmt.Dialector.(*mysql.Dialector)
+	if !ok || strings.Contains(v.ServerVersion, "MariaDB") {
+		return false
+	}
+
+	var major, minor, patch int
+	fmt.Sscanf(v.ServerVersion, "%d.%d.%d", &major, &minor, &patch)
+	return major > 8 || major == 8 && (minor > 0 || patch >= 17)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonExample converts value to the json document matched by JSONContains, it unwraps JSONType and drops struct
+// fields with zero value, values marshaled by themselves are kept as they are
+func jsonExample(value reflect.Value) interface{} {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil
+	}
+
+	typ := value.Type()
+	if typ.PkgPath() == reflect.TypeOf(JSON{}).PkgPath() && strings.HasPrefix(typ.Name(), "JSONType[") {
+		return jsonExample(value.MethodByName("Data").Call(nil)[0])
+	}
+	if typ.Implements(jsonMarshalerType) || typ.Implements(textMarshalerType) ||
+		reflect.PtrTo(typ).Implements(jsonMarshalerType) || reflect.PtrTo(typ).Implements(textMarshalerType) {
+		return value.Interface()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		example := map[string]interface{}{}
+		writeJSONExampleFields(example, value)
+		return example
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return value.Interface()
+		}
+		example := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			example[iter.Key().String()] = jsonExample(iter.Value())
+		}
+		return example
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		example := make([]interface{}, value.Len())
+		for i := range example {
+			example[i] = jsonExample(value.Index(i))
+		}
+		return example
+	}
+	return value.Interface()
+}
+
+// writeJSONExampleFields writes the non zero exported fields of struct value to example by their json names
+func writeJSONExampleFields(example map[string]interface{}, value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field, fieldValue := value.Type().Field(i), value.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || fieldValue.IsZero() {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				writeJSONExampleFields(example, embedded)
+				continue
 			}
 		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		example[name] = jsonExample(fieldValue)
+	}
+}
+
+// jsonArrayValues returns the elements of values if it is a slice or an array, or values itself
+func jsonArrayValues(values interface{}) []interface{} {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{values}
+	}
+
+	elems := make([]interface{}, rv.Len())
+	for idx := range elems {
+		elems[idx] = rv.Index(idx).Interface()
+	}
+	return elems
+}
+
+// writeMySQLJSONArray writes JSON_ARRAY of values, no values are an empty array, which every array contains
+func writeMySQLJSONArray(stmt *gorm.Statement, values []interface{}) {
+	stmt.WriteString("JSON_ARRAY")
+	if len(values) == 0 {
+		stmt.WriteString("()")
+		return
+	}
+	stmt.AddVar(stmt, values)
+}
+
+// jsonArrayCandidate returns values marshaled as JSON array, errors are added to stmt
+func jsonArrayCandidate(stmt *gorm.Statement, values []interface{}) string {
+	candidate, err := json.Marshal(values)
+	if err != nil {
+		_ = stmt.AddError(err)
+	}
+	return string(candidate)
+}
+
+// writeJSONArrayMatches writes the condition that the elements of the array at path, expanded by json_each,
+// match all or any of values, all distinct values are counted to match all
+func writeJSONArrayMatches(stmt *gorm.Statement, column string, path string, all bool, values interface{}) {
+	elems := jsonArrayValues(values)
+	if all {
+		stmt.WriteString("(SELECT COUNT(DISTINCT value) FROM ")
+	} else {
+		stmt.WriteString("EXISTS(SELECT 1 FROM ")
+	}
+	stmt.WriteString("json_each(")
+	stmt.WriteQuoted(column)
+	stmt.WriteByte(',')
+	stmt.AddVar(stmt, path)
+	stmt.WriteString(") WHERE value IN ")
+	stmt.AddVar(stmt, elems)
+	stmt.WriteByte(')')
+
+	if all {
+		// values are distinct by their json, as the database compares 1, int64(1) and 1.0 as the same number
+		distinct := map[string]bool{}
+		for _, elem := range elems {
+			distinct[jsonArrayCandidate(stmt, []interface{}{elem})] = true
+		}
+		stmt.WriteString(" = " + strconv.Itoa(len(distinct)))
 	}
 }
@@ -0,0 +1,139 @@
+package datatypes
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JSONAggregateExpression json aggregate expression, implements clause.Expression interface to use in select,
+// the results can be scanned into JSONSlice, JSONType and JSONMap
+type JSONAggregateExpression struct {
+	object bool
+	key    interface{}
+	value  interface{}
+	alias  string
+}
+
+// JSONArrayAgg aggregate values of rows into a json array, value is a column name or a clause.Expression
+// like JSONObject, groups without rows are aggregated into an empty array
+//
+//	DB.Table("books").Select("author_id, ?", JSONArrayAgg(JSONObject("id", "title")).As("books")).Group("author_id").Scan(&results)
+func JSONArrayAgg(value interface{}) *JSONAggregateExpression {
+	return &JSONAggregateExpression{value: value}
+}
+
+// JSONObjectAgg aggregate key-value pairs of rows into a json object, key and value are column names or
+// clause.Expression, groups without rows are aggregated into an empty object
+//
+//	DB.Table("books").Select("author_id, ?", JSONObjectAgg("isbn", "title").As("titles")).Group("author_id").Scan(&results)
+func JSONObjectAgg(key, value interface{}) *JSONAggregateExpression {
+	return &JSONAggregateExpression{object: true, key: key, value: value}
+}
+
+// As name the aggregated value with alias
+func (agg *JSONAggregateExpression) As(alias string) *JSONAggregateExpression {
+	agg.alias = alias
+	return agg
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite and postgres
+func (agg *JSONAggregateExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	var function, empty string
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		function, empty = "JSON_ARRAYAGG(", "JSON_ARRAY()"
+		if agg.object {
+			function, empty = "JSON_OBJECTAGG(", "JSON_OBJECT()"
+		}
+	case "sqlite":
+		function, empty = "json_group_array(", "json_array()"
+		if agg.object {
+			function, empty = "json_group_object(", "json_object()"
+		}
+	case "postgres":
+		function, empty = "jsonb_agg(", "'[]'::jsonb"
+		if agg.object {
+			function, empty = "jsonb_object_agg(", "'{}'::jsonb"
+		}
+	default:
+		_ = stmt.AddError(fmt.Errorf("json aggregate is not supported by %s", stmt.Dialector.Name()))
+		return
+	}
+
+	// aggregates of no rows are NULL, which can't be scanned into json types
+	stmt.WriteString("COALESCE(" + function)
+	if agg.object {
+		writeJSONAggregateArg(stmt, agg.key)
+		stmt.WriteByte(',')
+	}
+	writeJSONAggregateArg(stmt, agg.value)
+	stmt.WriteString(")," + empty + ")")
+
+	if agg.alias != "" {
+		stmt.WriteString(" AS ")
+		stmt.WriteQuoted(agg.alias)
+	}
+}
+
+func writeJSONAggregateArg(stmt *gorm.Statement, arg interface{}) {
+	if column, ok := arg.(string); ok {
+		stmt.WriteQuoted(column)
+	} else {
+		stmt.AddVar(stmt, arg)
+	}
+}
+
+type jsonObjectExpression struct {
+	columns []string
+}
+
+// JSONObject build a json object of columns for each row, keys are the names of the columns without table,
+// it can be aggregated by JSONArrayAgg
+//
+//	JSONObject("books.id", "books.title") // {"id": 1, "title": "title"}
+func JSONObject(columns ...string) clause.Expression {
+	return jsonObjectExpression{columns: columns}
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite and postgres
+func (object jsonObjectExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_OBJECT(")
+	case "sqlite":
+		stmt.WriteString("json_object(")
+	case "postgres":
+		stmt.WriteString("jsonb_build_object(")
+	default:
+		_ = stmt.AddError(fmt.Errorf("json object is not supported by %s", stmt.Dialector.Name()))
+		return
+	}
+
+	for idx, column := range object.columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.AddVar(stmt, column[strings.LastIndexByte(column, '.')+1:])
+		if stmt.Dialector.Name() == "postgres" {
+			stmt.WriteString("::text")
+		}
+		stmt.WriteByte(',')
+		stmt.WriteQuoted(column)
+	}
+	stmt.WriteByte(')')
+}
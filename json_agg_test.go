@@ -0,0 +1,83 @@
+package datatypes_test
+
+import (
+	"sort"
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONAggregate(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type Book struct {
+			gorm.Model
+			AuthorID uint
+			ISBN     string
+			Title    string
+		}
+
+		DB.Migrator().DropTable(&Book{})
+		if err := DB.Migrator().AutoMigrate(&Book{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		books := []Book{
+			{AuthorID: 1, ISBN: "isbn-1", Title: "title-1"},
+			{AuthorID: 1, ISBN: "isbn-2", Title: "title-2"},
+			{AuthorID: 2, ISBN: "isbn-3", Title: "title-3"},
+		}
+		if err := DB.Create(&books).Error; err != nil {
+			t.Errorf("Failed to create books %v", err)
+		}
+
+		type BookJSON struct {
+			ID    uint   `json:"id"`
+			Title string `json:"title"`
+		}
+
+		type Result struct {
+			AuthorID uint
+			Titles   datatypes.JSONSlice[string]
+			Books    datatypes.JSONType[[]BookJSON]
+			ISBNs    datatypes.JSONMap `gorm:"column:isbns"`
+		}
+
+		var results []Result
+		if err := DB.Table("books").Select("author_id, ?, ?, ?",
+			datatypes.JSONArrayAgg("title").As("titles"),
+			datatypes.JSONArrayAgg(datatypes.JSONObject("books.id", "books.title")).As("books"),
+			datatypes.JSONObjectAgg("isbn", "title").As("isbns"),
+		).Group("author_id").Order("author_id").Scan(&results).Error; err != nil {
+			t.Fatalf("failed to aggregate json, got error %v", err)
+		}
+
+		if len(results) != 2 {
+			t.Fatalf("should aggregate 2 authors, got %v", len(results))
+		}
+
+		titles := []string(results[0].Titles)
+		sort.Strings(titles)
+		AssertEqual(t, titles, []string{"title-1", "title-2"})
+		AssertEqual(t, results[1].Titles, datatypes.JSONSlice[string]{"title-3"})
+
+		aggregated := results[0].Books.Data()
+		sort.Slice(aggregated, func(i, j int) bool { return aggregated[i].ID < aggregated[j].ID })
+		AssertEqual(t, aggregated, []BookJSON{{ID: books[0].ID, Title: "title-1"}, {ID: books[1].ID, Title: "title-2"}})
+		AssertEqual(t, results[0].ISBNs, datatypes.JSONMap{"isbn-1": "title-1", "isbn-2": "title-2"})
+		AssertEqual(t, results[1].ISBNs, datatypes.JSONMap{"isbn-3": "title-3"})
+
+		var empty Result
+		if err := DB.Table("books").Select("?, ?, ?",
+			datatypes.JSONArrayAgg("title").As("titles"),
+			datatypes.JSONArrayAgg(datatypes.JSONObject("id", "title")).As("books"),
+			datatypes.JSONObjectAgg("isbn", "title").As("isbns"),
+		).Where("author_id = ?", 3).Scan(&empty).Error; err != nil {
+			t.Fatalf("failed to aggregate json of no rows, got error %v", err)
+		}
+		AssertEqual(t, empty.Titles, datatypes.JSONSlice[string]{})
+		AssertEqual(t, empty.Books.Data(), []BookJSON{})
+		AssertEqual(t, empty.ISBNs, datatypes.JSONMap{})
+	}
+}
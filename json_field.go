@@ -0,0 +1,148 @@
+package datatypes
+
+// JSONField json path of column with values of type V, used by the code generated by cmd/datatypes-gen
+//
+//	name := NewJSONField[string]("attributes", "orgs", "orga")
+//	DB.Where(name.Eq("orga")).Find(&users)
+//	DB.Model(&user).UpdateColumn("attributes", name.Set("orgb"))
+type JSONField[V any] struct {
+	column string
+	keys   []string
+}
+
+// NewJSONField return the json path of keys in column with values of type V
+func NewJSONField[V any](column string, keys ...string) JSONField[V] {
+	return JSONField[V]{column: column, keys: keys}
+}
+
+// Column return the json column of the field
+func (field JSONField[V]) Column() string {
+	return field.column
+}
+
+// Keys return the keys of the field, which can be used with JSONQuery
+func (field JSONField[V]) Keys() []string {
+	return field.keys
+}
+
+// Path return the path like `orgs.orga`, which can be used with JSONQuery("attributes").Extract and JSONSet
+func (field JSONField[V]) Path() string {
+	return JSONKeysPath(field.keys...)
+}
+
+// HasKey checks if the field exists
+func (field JSONField[V]) HasKey() *JSONQueryExpression {
+	return JSONQuery(field.column).HasKey(field.keys...)
+}
+
+// Eq checks if the field equals value
+func (field JSONField[V]) Eq(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Equals(value, field.keys...)
+}
+
+// Ne checks if the field is not equal to value
+func (field JSONField[V]) Ne(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).NotEquals(value, field.keys...)
+}
+
+// Gt checks if the field is greater than value
+func (field JSONField[V]) Gt(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Gt(value, field.keys...)
+}
+
+// Gte checks if the field is greater than or equal to value
+func (field JSONField[V]) Gte(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Gte(value, field.keys...)
+}
+
+// Lt checks if the field is less than value
+func (field JSONField[V]) Lt(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Lt(value, field.keys...)
+}
+
+// Lte checks if the field is less than or equal to value
+func (field JSONField[V]) Lte(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Lte(value, field.keys...)
+}
+
+// Between checks if the field is between lower and upper, inclusive
+func (field JSONField[V]) Between(lower, upper V) *JSONQueryExpression {
+	return JSONQuery(field.column).Between(lower, upper, field.keys...)
+}
+
+// In checks if the field is one of values
+func (field JSONField[V]) In(values ...V) *JSONQueryExpression {
+	return JSONQuery(field.column).In(values, field.keys...)
+}
+
+// Contains checks if the json of the field contains value like JSONQueryExpression.Contains
+func (field JSONField[V]) Contains(value V) *JSONQueryExpression {
+	return JSONQuery(field.column).Contains(value, field.keys...)
+}
+
+// Set update the field to value
+func (field JSONField[V]) Set(value V) *JSONSetExpression {
+	return JSONSet(field.column).Set(field.Path(), value)
+}
+
+// Remove remove the field
+func (field JSONField[V]) Remove() *JSONRemoveExpression {
+	return JSONRemove(field.column).Remove(field.Path())
+}
+
+// JSONSliceField json path of column with arrays of elements of type E, used by the code generated by cmd/datatypes-gen
+//
+//	tags := NewJSONSliceField[string]("attributes", "tags")
+//	DB.Where(tags.Contains("tag1")).Find(&users)
+//	DB.Model(&user).UpdateColumn("attributes", tags.Append("tag3"))
+type JSONSliceField[E any] struct {
+	JSONField[[]E]
+}
+
+// NewJSONSliceField return the json path of keys in column with arrays of elements of type E
+func NewJSONSliceField[E any](column string, keys ...string) JSONSliceField[E] {
+	return JSONSliceField[E]{JSONField: NewJSONField[[]E](column, keys...)}
+}
+
+// Contains checks if the array contains value
+func (field JSONSliceField[E]) Contains(value E) *JSONArrayExpression {
+	return JSONArrayQuery(field.column).Contains(value, field.keys...)
+}
+
+// ContainsAll checks if the array contains all of values
+func (field JSONSliceField[E]) ContainsAll(values ...E) *JSONArrayExpression {
+	return JSONArrayQuery(field.column).ContainsAll(values, field.keys...)
+}
+
+// ContainsAny checks if the array contains any of values
+func (field JSONSliceField[E]) ContainsAny(values ...E) *JSONArrayExpression {
+	return JSONArrayQuery(field.column).ContainsAny(values, field.keys...)
+}
+
+// Length return the length of the array
+func (field JSONSliceField[E]) Length() *JSONExtractExpression {
+	return JSONArrayQuery(field.column).Length(field.keys...)
+}
+
+// Append add values to the end of the array
+func (field JSONSliceField[E]) Append(values ...E) *JSONArrayUpdateExpression {
+	return JSONArrayUpdate(field.column).Append(field.Path(), jsonFieldValues(values)...)
+}
+
+// Prepend add values to the start of the array, this method is not supported by SQL Server
+func (field JSONSliceField[E]) Prepend(values ...E) *JSONArrayUpdateExpression {
+	return JSONArrayUpdate(field.column).Prepend(field.Path(), jsonFieldValues(values)...)
+}
+
+// RemoveValues remove elements equal to any of values from the array, this method is not supported by SQL Server
+func (field JSONSliceField[E]) RemoveValues(values ...E) *JSONArrayUpdateExpression {
+	return JSONArrayUpdate(field.column).Remove(field.Path(), jsonFieldValues(values)...)
+}
+
+func jsonFieldValues[E any](values []E) []interface{} {
+	result := make([]interface{}, len(values))
+	for idx, value := range values {
+		result[idx] = value
+	}
+	return result
+}
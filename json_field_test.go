@@ -0,0 +1,72 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONField(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"age": 18, "orgs": {"orga": "orga"}, "tags": ["tag1", "tag2"]}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"age": 28, "orgs": {"orga": "orgb"}, "tags": ["tag3"]}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		var (
+			age  = datatypes.NewJSONField[int]("attributes", "age")
+			orga = datatypes.NewJSONField[string]("attributes", "orgs", "orga")
+			tags = datatypes.NewJSONSliceField[string]("attributes", "tags")
+		)
+		AssertEqual(t, orga.Path(), "orgs.orga")
+		AssertEqual(t, datatypes.JSONKeysPath("orgs", "org a", ""), `orgs."org a".""`)
+
+		find := func(query interface{}) []string {
+			var results []UserWithJSON
+			if err := DB.Where(query).Order("id").Find(&results).Error; err != nil {
+				t.Fatalf("failed to find users with json field, got error %v", err)
+			}
+
+			names := make([]string, 0, len(results))
+			for _, result := range results {
+				names = append(names, result.Name)
+			}
+			return names
+		}
+
+		AssertEqual(t, find(orga.Eq("orgb")), []string{"json-2"})
+		AssertEqual(t, find(age.Lt(20)), []string{"json-1"})
+		AssertEqual(t, find(age.In(18, 28)), []string{"json-1", "json-2"})
+		AssertEqual(t, find(tags.Contains("tag2")), []string{"json-1"})
+		AssertEqual(t, find(tags.ContainsAny("tag1", "tag3")), []string{"json-1", "json-2"})
+
+		if err := DB.Model(&users[0]).UpdateColumn("attributes", orga.Set("orgc")).Error; err != nil {
+			t.Fatalf("failed to set json field, got error %v", err)
+		}
+		if err := DB.Model(&users[1]).UpdateColumn("attributes", tags.Append("tag4")).Error; err != nil {
+			t.Fatalf("failed to append json field, got error %v", err)
+		}
+		AssertEqual(t, find(orga.Eq("orgc")), []string{"json-1"})
+		AssertEqual(t, find(tags.ContainsAll("tag3", "tag4")), []string{"json-2"})
+	}
+}
@@ -0,0 +1,609 @@
+package datatypes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// JSONMigrator migrator of json columns, AutoMigrate migrates the tables with the migrator of db, then creates
+// the generated columns and indexes of json paths declared by the jsonindex tag of json fields, generated columns
+// and indexes whose definition changed are recreated on MySQL, PostgreSQL and SQLite
+//
+//	type User struct {
+//		ID         uint
+//		Attributes datatypes.JSON `gorm:"jsonindex:idx_user_email,path:email,type:varchar(255),unique"`
+//	}
+//
+//	datatypes.NewJSONMigrator(DB).AutoMigrate(&User{})
+//	// MySQL
+//	// ALTER TABLE `users` ADD `attributes_email` varchar(255) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(`attributes`,'$.email'))) VIRTUAL
+//	// CREATE UNIQUE INDEX `idx_user_email` ON `users` (`attributes_email`)
+//
+// the options of the jsonindex tag are
+//
+//	path: the path of the indexed value, the same as JSONQueryExpression.Extract, required
+//	type: the type of the generated column, defaults to varchar(255)
+//	column: the name of the generated column, defaults to the name of the json column and the keys of path joined by _
+//	unique: creates an unique index
+//	stored: stores the generated column, PostgreSQL only supports stored generated columns and SQLite can't add them
+//	expression: indexes the expression of path instead of a generated column, type casts the value if set,
+//	  MySQL defaults to CHAR(255), SQL Server is not supported
+//	gin: creates a PostgreSQL GIN index of the column, with the operator class jsonb_ops or gin:jsonb_path_ops,
+//	  skipped by other dialects
+//	array: creates a MySQL multi-valued index of the array of path, type defaults to CHAR(64), skipped by other dialects
+//
+//	type Post struct {
+//		ID         uint
+//		Attributes datatypes.JSONMap           `gorm:"jsonindex:idx_post_attributes,gin:jsonb_path_ops"`
+//		Tags       datatypes.JSONSlice[string] `gorm:"jsonindex:idx_post_tags,array"`
+//		Meta       datatypes.JSONType[Meta]    `gorm:"jsonindex:idx_post_author,path:author,expression"`
+//	}
+//	// PostgreSQL
+//	// CREATE INDEX "idx_post_attributes" ON "posts" USING GIN ("attributes" jsonb_path_ops)
+//	// CREATE INDEX "idx_post_author" ON "posts" (("meta" ->> 'author'))
+//	// MySQL
+//	// CREATE INDEX `idx_post_tags` ON `posts` ((CAST(`tags`->'$' AS CHAR(64) ARRAY)))
+//
+// Equals, Likes and comparisons of JSONQuery on the path use the generated column or the indexed expression when
+// querying the model
+type JSONMigrator struct {
+	gorm.Migrator
+	db *gorm.DB
+}
+
+// NewJSONMigrator return the json migrator of db
+func NewJSONMigrator(db *gorm.DB) *JSONMigrator {
+	return &JSONMigrator{Migrator: db.Migrator(), db: db}
+}
+
+// AutoMigrate migrates values, and creates the generated columns and indexes of their json paths if missing
+func (m *JSONMigrator) AutoMigrate(values ...interface{}) error {
+	if err := m.Migrator.AutoMigrate(values...); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		stmt := &gorm.Statement{DB: m.db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			index, err := parseJSONIndex(field)
+			if err != nil {
+				return err
+			} else if index == nil {
+				continue
+			}
+
+			if index.skipped(m.db.Dialector.Name()) {
+				continue
+			}
+
+			if index.kind == jsonIndexGenerated {
+				if err := m.createJSONColumn(value, stmt.Table, index); err != nil {
+					return err
+				}
+			}
+
+			if err := m.createJSONIndex(value, stmt.Table, index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createJSONIndex creates index if missing, or recreates it if its definition changed
+func (m *JSONMigrator) createJSONIndex(value interface{}, table string, index *jsonIndex) error {
+	dryRun := m.db.Session(&gorm.Session{DryRun: true, NewDB: true}).Exec("?", index.indexExpr(table))
+	if dryRun.Error != nil {
+		return dryRun.Error
+	}
+	sql := dryRun.Statement.SQL.String()
+	comment := jsonIndexComment(sql)
+
+	if m.HasIndex(value, index.name) {
+		if definition, ok, err := m.jsonIndexDefinition(table, index.name); err != nil {
+			return err
+		} else if !ok || definition == sql || definition == comment {
+			return nil
+		}
+		if err := m.DropIndex(value, index.name); err != nil {
+			return err
+		}
+	}
+
+	switch m.db.Dialector.Name() {
+	case "mysql":
+		return m.db.Exec(sql + " COMMENT '" + comment + "'").Error
+	case "postgres":
+		if err := m.db.Exec(sql).Error; err != nil {
+			return err
+		}
+		return m.db.Exec("COMMENT ON INDEX ? IS '"+comment+"'", clause.Column{Name: index.name}).Error
+	}
+	return m.db.Exec(sql).Error
+}
+
+// jsonIndexDefinition returns the definition of the existing index, which is the sql of SQLite indexes, and
+// the comment of jsonIndexComment for MySQL and PostgreSQL indexes, returns false if it can't be compared
+func (m *JSONMigrator) jsonIndexDefinition(table, name string) (string, bool, error) {
+	switch m.db.Dialector.Name() {
+	case "sqlite":
+		return m.scanJSONDefinition("SELECT sql FROM sqlite_master WHERE type = ? AND tbl_name = ? AND name = ?", "index", table, name)
+	case "mysql":
+		return m.scanJSONDefinition("SELECT INDEX_COMMENT FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ? LIMIT 1", table, name)
+	case "postgres":
+		return m.scanJSONDefinition("SELECT obj_description(c.oid, 'pg_class') FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = CURRENT_SCHEMA() AND c.relname = ? AND c.relkind = 'i'", name)
+	}
+	return "", false, nil
+}
+
+// createJSONColumn adds the generated column of index if missing, or drops and adds it again with its index if
+// its definition changed
+func (m *JSONMigrator) createJSONColumn(value interface{}, table string, index *jsonIndex) error {
+	dryRun := m.db.Session(&gorm.Session{DryRun: true, NewDB: true}).Exec("?", index.columnExpr())
+	if dryRun.Error != nil {
+		return dryRun.Error
+	}
+	column := dryRun.Statement.SQL.String()
+	comment := jsonIndexComment(column)
+
+	if m.HasColumn(value, index.column) {
+		// SQLite keeps the definition of added columns in the sql of the table
+		definition, ok, err := m.jsonColumnDefinition(table, index.column)
+		if err != nil {
+			return err
+		} else if !ok || definition == comment || strings.Contains(definition, strings.TrimPrefix(column, "ADD COLUMN ")) {
+			return nil
+		}
+
+		if m.HasIndex(value, index.name) {
+			if err := m.DropIndex(value, index.name); err != nil {
+				return err
+			}
+		}
+		if err := m.db.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: table}, clause.Column{Name: index.column}).Error; err != nil {
+			return err
+		}
+	}
+
+	switch m.db.Dialector.Name() {
+	case "mysql":
+		return m.db.Exec("ALTER TABLE ? "+column+" COMMENT '"+comment+"'", clause.Table{Name: table}).Error
+	case "postgres":
+		if err := m.db.Exec("ALTER TABLE ? "+column, clause.Table{Name: table}).Error; err != nil {
+			return err
+		}
+		return m.db.Exec("COMMENT ON COLUMN ?.? IS '"+comment+"'", clause.Table{Name: table}, clause.Column{Name: index.column}).Error
+	}
+	return m.db.Exec("ALTER TABLE ? "+column, clause.Table{Name: table}).Error
+}
+
+// jsonColumnDefinition returns the definition of the existing generated column, which is the sql of the table
+// for SQLite, and the comment of jsonIndexComment for MySQL and PostgreSQL, returns false if it can't be compared
+func (m *JSONMigrator) jsonColumnDefinition(table, name string) (string, bool, error) {
+	switch m.db.Dialector.Name() {
+	case "sqlite":
+		return m.scanJSONDefinition("SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", "table", table)
+	case "mysql":
+		return m.scanJSONDefinition("SELECT COLUMN_COMMENT FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?", table, name)
+	case "postgres":
+		return m.scanJSONDefinition("SELECT col_description(c.oid, a.attnum) FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_attribute a ON a.attrelid = c.oid WHERE n.nspname = CURRENT_SCHEMA() AND c.relname = ? AND a.attname = ?", table, name)
+	}
+	return "", false, nil
+}
+
+// scanJSONDefinition returns the definition selected by query, which is empty if it is NULL or missing
+func (m *JSONMigrator) scanJSONDefinition(query string, values ...interface{}) (string, bool, error) {
+	var definition *string
+	if err := m.db.Raw(query, values...).Row().Scan(&definition); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", false, err
+	}
+
+	if definition == nil {
+		return "", true, nil
+	}
+	return *definition, true, nil
+}
+
+// jsonIndexComment returns the comment recording the definition of the index created by sql
+func jsonIndexComment(sql string) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(sql))
+	return fmt.Sprintf("datatypes:jsonindex:%x", hash.Sum64())
+}
+
+// jsonIndexKind is the kind of the index declared by the jsonindex tag
+type jsonIndexKind int
+
+const (
+	jsonIndexGenerated  jsonIndexKind = iota // index of the generated column of the path
+	jsonIndexExpression                      // index of the expression of the path
+	jsonIndexGIN                             // PostgreSQL GIN index of the column
+	jsonIndexArray                           // MySQL multi-valued index of the array of the path
+)
+
+// jsonIndex is the generated column and index of a json path declared by the jsonindex tag
+type jsonIndex struct {
+	kind     jsonIndexKind
+	name     string
+	field    string
+	path     jsonPath
+	dataType string
+	column   string
+	opclass  string
+	unique   bool
+	stored   bool
+}
+
+// parseJSONIndex parses the jsonindex tag of field, returns nil if field has no jsonindex tag
+func parseJSONIndex(field *schema.Field) (*jsonIndex, error) {
+	tag, ok := field.TagSettings["JSONINDEX"]
+	if !ok {
+		return nil, nil
+	}
+
+	// commas of types like decimal(10,2) are kept
+	var options []string
+	for _, option := range strings.Split(tag, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(option), ":")
+		switch strings.ToLower(name) {
+		case "path", "type", "column", "unique", "stored", "expression", "gin", "array":
+			options = append(options, strings.TrimSpace(option))
+		default:
+			if len(options) == 0 {
+				options = append(options, strings.TrimSpace(option))
+			} else {
+				options[len(options)-1] += "," + option
+			}
+		}
+	}
+
+	index := &jsonIndex{field: field.DBName}
+	var path string
+	for idx, option := range options {
+		name, value, _ := strings.Cut(option, ":")
+		switch strings.ToLower(name) {
+		case "path":
+			path = value
+		case "type":
+			index.dataType = value
+		case "column":
+			index.column = value
+		case "unique":
+			index.unique = true
+		case "stored":
+			index.stored = true
+		case "expression":
+			index.kind = jsonIndexExpression
+		case "gin":
+			index.kind, index.opclass = jsonIndexGIN, "jsonb_ops"
+			if value != "" {
+				index.opclass = value
+			}
+		case "array":
+			index.kind = jsonIndexArray
+		default:
+			if idx == 0 {
+				index.name = option
+			}
+		}
+	}
+
+	var err error
+	if index.path, err = parseJSONPath(path); err != nil {
+		return nil, fmt.Errorf("invalid jsonindex of %s: %w", field.Name, err)
+	}
+	if len(index.path.wildcards()) > 0 || index.path.has(jsonPathLast) {
+		return nil, fmt.Errorf("invalid jsonindex of %s: path %q should select a value without [*] and [last]", field.Name, path)
+	}
+
+	switch index.kind {
+	case jsonIndexGenerated, jsonIndexExpression:
+		if len(index.path) == 0 {
+			return nil, fmt.Errorf("invalid jsonindex of %s: path is required", field.Name)
+		}
+	case jsonIndexGIN:
+		if len(index.path) > 0 || index.unique {
+			return nil, fmt.Errorf("invalid jsonindex of %s: gin indexes the whole column and can't be unique", field.Name)
+		}
+		if index.opclass != "jsonb_ops" && index.opclass != "jsonb_path_ops" {
+			return nil, fmt.Errorf("invalid jsonindex of %s: unknown gin operator class %q", field.Name, index.opclass)
+		}
+	case jsonIndexArray:
+		if index.dataType == "" {
+			index.dataType = "CHAR(64)"
+		}
+	}
+
+	// the name of the generated column defaults to the json column and the keys of path
+	name := field.DBName
+	for _, key := range index.path.keys() {
+		name += "_" + strings.Map(func(r rune) rune {
+			if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, key)
+	}
+
+	if index.kind == jsonIndexGenerated {
+		if index.dataType == "" {
+			index.dataType = "varchar(255)"
+		}
+		if index.column == "" {
+			index.column = name
+		}
+		name = index.column
+	}
+	if index.name == "" {
+		index.name = "idx_" + name
+	}
+	return index, nil
+}
+
+// columnExpr returns the definition of the generated column to add
+func (index *jsonIndex) columnExpr() clause.Expression {
+	return jsonIndexColumnExpr{index: index}
+}
+
+type jsonIndexColumnExpr struct {
+	index *jsonIndex
+}
+
+// Build implements clause.Expression
+func (expr jsonIndexColumnExpr) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	index := expr.index
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("ADD ")
+		stmt.WriteQuoted(index.column)
+		stmt.WriteString(" " + index.dataType + " GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("," + index.path.literal() + ")))")
+		if index.stored {
+			stmt.WriteString(" STORED")
+		} else {
+			stmt.WriteString(" VIRTUAL")
+		}
+	case "sqlite":
+		// only virtual columns can be added
+		stmt.WriteString("ADD COLUMN ")
+		stmt.WriteQuoted(index.column)
+		stmt.WriteString(" " + index.dataType + " GENERATED ALWAYS AS (json_extract(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("," + sqlStringLiteral(index.path.sql("sqlite")) + ")) VIRTUAL")
+	case "postgres":
+		stmt.WriteString("ADD COLUMN ")
+		stmt.WriteQuoted(index.column)
+		stmt.WriteString(" " + index.dataType + " GENERATED ALWAYS AS (CAST(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("::jsonb #>> " + sqlStringLiteral(index.path.array()) + " AS " + index.dataType + ")) STORED")
+	case "sqlserver":
+		stmt.WriteString("ADD ")
+		stmt.WriteQuoted(index.column)
+		stmt.WriteString(" AS CAST(JSON_VALUE(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("," + sqlStringLiteral(index.path.sql("sqlserver")) + ") AS " + index.dataType + ")")
+		if index.stored {
+			stmt.WriteString(" PERSISTED")
+		}
+	default:
+		_ = stmt.AddError(fmt.Errorf("json index is not supported by %s", stmt.Dialector.Name()))
+	}
+}
+
+// skipped reports if the index is specific to other dialects than dialect
+func (index *jsonIndex) skipped(dialect string) bool {
+	switch index.kind {
+	case jsonIndexGIN:
+		return dialect != "postgres"
+	case jsonIndexArray:
+		return dialect != "mysql"
+	}
+	return false
+}
+
+// indexExpr returns the statement creating the index on table
+func (index *jsonIndex) indexExpr(table string) clause.Expression {
+	return jsonIndexExpr{index: index, table: table}
+}
+
+type jsonIndexExpr struct {
+	index *jsonIndex
+	table string
+}
+
+// Build implements clause.Expression
+func (expr jsonIndexExpr) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	index := expr.index
+	if index.unique {
+		stmt.WriteString("CREATE UNIQUE INDEX ")
+	} else {
+		stmt.WriteString("CREATE INDEX ")
+	}
+	stmt.WriteQuoted(index.name)
+	stmt.WriteString(" ON ")
+	stmt.WriteQuoted(expr.table)
+
+	switch index.kind {
+	case jsonIndexGenerated:
+		stmt.WriteString(" (")
+		stmt.WriteQuoted(index.column)
+		stmt.WriteByte(')')
+	case jsonIndexExpression:
+		// the value of PostgreSQL is already parenthesized or a cast
+		if stmt.Dialector.Name() == "postgres" {
+			stmt.WriteString(" (")
+			index.valueExpr().Build(stmt)
+			stmt.WriteByte(')')
+		} else {
+			stmt.WriteString(" ((")
+			index.valueExpr().Build(stmt)
+			stmt.WriteString("))")
+		}
+	case jsonIndexGIN:
+		stmt.WriteString(" USING GIN (")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString(" " + index.opclass + ")")
+	case jsonIndexArray:
+		stmt.WriteString(" ((CAST(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("->" + index.path.literal() + " AS " + index.dataType + " ARRAY)))")
+	}
+}
+
+// valueExpr returns the value of the path, the generated column or the indexed expression
+func (index *jsonIndex) valueExpr() clause.Expression {
+	if index.kind == jsonIndexGenerated {
+		return clause.Expr{SQL: "?", Vars: []interface{}{clause.Column{Name: index.column}}}
+	}
+	return jsonIndexValueExpr{index: index}
+}
+
+type jsonIndexValueExpr struct {
+	index *jsonIndex
+}
+
+// Build implements clause.Expression
+func (expr jsonIndexValueExpr) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	index := expr.index
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		// functional indexes can't index text, the value is always cast
+		dataType := index.dataType
+		if dataType == "" {
+			dataType = "CHAR(255)"
+		}
+		stmt.WriteString("CAST(JSON_UNQUOTE(JSON_EXTRACT(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("," + index.path.literal() + ")) AS " + dataType + ")")
+	case "sqlite":
+		if index.dataType != "" {
+			stmt.WriteString("CAST(")
+		}
+		stmt.WriteString("json_extract(")
+		stmt.WriteQuoted(index.field)
+		stmt.WriteString("," + sqlStringLiteral(index.path.sql("sqlite")) + ")")
+		if index.dataType != "" {
+			stmt.WriteString(" AS " + index.dataType + ")")
+		}
+	case "postgres":
+		if index.dataType != "" {
+			stmt.WriteString("CAST(")
+		} else {
+			stmt.WriteByte('(')
+		}
+		stmt.WriteQuoted(index.field)
+		if len(index.path) == 1 && index.path[0].kind == jsonPathKey {
+			stmt.WriteString(" ->> " + sqlStringLiteral(index.path[0].key))
+		} else {
+			stmt.WriteString(" #>> " + sqlStringLiteral(index.path.array()))
+		}
+		if index.dataType != "" {
+			stmt.WriteString(" AS " + index.dataType)
+		}
+		stmt.WriteByte(')')
+	default:
+		_ = stmt.AddError(fmt.Errorf("json expression index is not supported by %s", stmt.Dialector.Name()))
+	}
+}
+
+// sqlStringLiteral quotes str as string literal of SQL
+func sqlStringLiteral(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
+
+// jsonSchemaIndexes caches the parsed jsonindex tags of schemas, which are cached by gorm too
+var jsonSchemaIndexes sync.Map
+
+// jsonIndexesOf returns the valid jsonindex tags of the fields of s, the tags are parsed once for every schema
+func jsonIndexesOf(s *schema.Schema) map[*schema.Field]*jsonIndex {
+	if indexes, ok := jsonSchemaIndexes.Load(s); ok {
+		return indexes.(map[*schema.Field]*jsonIndex)
+	}
+
+	indexes := map[*schema.Field]*jsonIndex{}
+	for _, field := range s.Fields {
+		if index, err := parseJSONIndex(field); err == nil && index != nil {
+			indexes[field] = index
+		}
+	}
+	cached, _ := jsonSchemaIndexes.LoadOrStore(s, indexes)
+	return cached.(map[*schema.Field]*jsonIndex)
+}
+
+// jsonIndexValue returns the generated column or the indexed expression of the path of column declared by
+// the schema of stmt, and the kind of values it can be compared with
+func jsonIndexValue(stmt *gorm.Statement, src jsonSource, path jsonPath) (clause.Expression, jsonValueKind, bool) {
+	if stmt.Schema == nil || src.alias != "" || len(path) == 0 {
+		return nil, 0, false
+	}
+
+	field := stmt.Schema.LookUpField(src.column)
+	if field == nil {
+		return nil, 0, false
+	}
+
+	index := jsonIndexesOf(stmt.Schema)[field]
+	if index == nil || index.skipped(stmt.Dialector.Name()) || index.path.sql("mysql") != path.sql("mysql") {
+		return nil, 0, false
+	}
+
+	kind, ok := index.valueKind()
+	if !ok {
+		return nil, 0, false
+	}
+
+	switch index.kind {
+	case jsonIndexGenerated:
+		return index.valueExpr(), kind, true
+	case jsonIndexExpression:
+		if stmt.Dialector.Name() != "sqlserver" {
+			return index.valueExpr(), kind, true
+		}
+	}
+	return nil, 0, false
+}
+
+// valueKind returns the kind of the indexed value by its type, false if it is neither text nor number,
+// expression indexes without type are text
+func (index *jsonIndex) valueKind() (jsonValueKind, bool) {
+	dataType, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(index.dataType)), "(")
+	switch {
+	case dataType == "":
+		return jsonKindText, index.kind == jsonIndexExpression
+	case strings.Contains(dataType, "char"), strings.Contains(dataType, "text"):
+		return jsonKindText, true
+	case strings.Contains(dataType, "int"), strings.Contains(dataType, "decimal"), strings.Contains(dataType, "numeric"),
+		strings.Contains(dataType, "float"), strings.Contains(dataType, "double"), strings.Contains(dataType, "real"),
+		strings.Contains(dataType, "signed"):
+		return jsonKindNumber, true
+	}
+	return 0, false
+}
@@ -0,0 +1,247 @@
+package datatypes_test
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONMigratorIndex(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSONIndex struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON `gorm:"jsonindex:idx_user_email,path:email,type:varchar(255),unique"`
+			Stats      datatypes.JSON `gorm:"jsonindex:path:scores.total,type:decimal(10,2)"`
+			Profile    datatypes.JSON `gorm:"jsonindex:path:age"`
+		}
+
+		DB.Migrator().DropTable(&UserWithJSONIndex{})
+		migrator := datatypes.NewJSONMigrator(DB)
+		for i := 0; i < 2; i++ {
+			if err := migrator.AutoMigrate(&UserWithJSONIndex{}); err != nil {
+				t.Fatalf("failed to migrate, got error: %v", err)
+			}
+		}
+
+		for _, column := range []string{"attributes_email", "stats_scores_total"} {
+			if !DB.Migrator().HasColumn(&UserWithJSONIndex{}, column) {
+				t.Errorf("should have generated column %v", column)
+			}
+		}
+		for _, index := range []string{"idx_user_email", "idx_stats_scores_total"} {
+			if !DB.Migrator().HasIndex(&UserWithJSONIndex{}, index) {
+				t.Errorf("should have index %v", index)
+			}
+		}
+
+		users := []UserWithJSONIndex{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"email": "json-1@example.com"}`),
+			Stats:      datatypes.JSON(`{"scores": {"total": 10.5}}`),
+			Profile:    datatypes.JSON(`{"age": 8}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"email": "json-2@example.com"}`),
+			Stats:      datatypes.JSON(`{"scores": {"total": 20}}`),
+			Profile:    datatypes.JSON(`{"age": 28}`),
+		}, {
+			Name:       "json-4",
+			Attributes: datatypes.JSON(`{"email": "json-4@example.com"}`),
+			Profile:    datatypes.JSON(`{"age": 100}`),
+		}, {
+			Name:    "json-5",
+			Profile: datatypes.JSON(`{"age": null}`),
+		}}
+		if err := DB.Create(&users).Error; err != nil {
+			t.Fatalf("Failed to create users %v", err)
+		}
+
+		if err := DB.Create(&UserWithJSONIndex{Name: "json-3", Attributes: datatypes.JSON(`{"email": "json-1@example.com"}`)}).Error; err == nil {
+			t.Errorf("should fail to create user with duplicated email")
+		}
+
+		query := datatypes.JSONQuery("attributes").Equals("json-2@example.com", "email")
+		sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(query).Find(&[]UserWithJSONIndex{})
+		})
+		if !strings.Contains(sql, "attributes_email") {
+			t.Errorf("query should use generated column, got %v", sql)
+		}
+
+		var result UserWithJSONIndex
+		if err := DB.First(&result, query).Error; err != nil {
+			t.Fatalf("failed to find user with generated column, got error %v", err)
+		}
+		AssertEqual(t, result.Name, "json-2")
+
+		var results []UserWithJSONIndex
+		if err := DB.Where(datatypes.JSONQuery("stats").Gt(15, "scores", "total")).Find(&results).Error; err != nil || len(results) != 1 {
+			t.Fatalf("failed to compare generated column, got %v, error %v", len(results), err)
+		}
+		AssertEqual(t, results[0].Name, "json-2")
+
+		// numbers are not compared with the varchar generated column of the default type
+		query = datatypes.JSONQuery("profile").Gt(18, "age")
+		sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(query).Find(&[]UserWithJSONIndex{})
+		})
+		if strings.Contains(sql, "profile_age") {
+			t.Errorf("query should not compare numbers with varchar generated column, got %v", sql)
+		}
+
+		var names []string
+		if err := DB.Model(&UserWithJSONIndex{}).Where(query).Order("id").Pluck("name", &names).Error; err != nil {
+			t.Fatalf("failed to compare numbers of path with generated column, got error %v", err)
+		}
+		AssertEqual(t, names, []string{"json-2", "json-4"})
+
+		// strings only match json strings, not the numbers or nulls of the same text in the generated column
+		for _, value := range []string{"100", "null"} {
+			query := datatypes.JSONQuery("profile").Equals(value, "age")
+			sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Where(query).Find(&[]UserWithJSONIndex{})
+			})
+			if !strings.Contains(sql, "profile_age") {
+				t.Errorf("query should use generated column, got %v", sql)
+			}
+
+			names = nil
+			if err := DB.Model(&UserWithJSONIndex{}).Where(query).Pluck("name", &names).Error; err != nil {
+				t.Fatalf("failed to find users with generated column, got error %v", err)
+			}
+			AssertEqual(t, len(names), 0)
+		}
+
+		// the generated column of the changed type is recreated with its index
+		if DB.Dialector.Name() != "sqlserver" {
+			if err := migrator.AutoMigrate(&UserWithJSONIndexV2{}); err != nil {
+				t.Fatalf("failed to migrate changed generated column, got error: %v", err)
+			}
+			if !DB.Migrator().HasIndex(&UserWithJSONIndexV2{}, "idx_profile_age") {
+				t.Errorf("should have index idx_profile_age")
+			}
+
+			sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Where(query).Find(&[]UserWithJSONIndexV2{})
+			})
+			if !strings.Contains(sql, "profile_age") {
+				t.Errorf("query should compare numbers with integer generated column, got %v", sql)
+			}
+
+			names = nil
+			if err := DB.Model(&UserWithJSONIndexV2{}).Where(query).Order("id").Pluck("name", &names).Error; err != nil {
+				t.Fatalf("failed to compare numbers with changed generated column, got error %v", err)
+			}
+			AssertEqual(t, names, []string{"json-2", "json-4"})
+		}
+	}
+}
+
+// UserWithJSONIndexV2 changes the type of the generated column of profile age
+type UserWithJSONIndexV2 struct {
+	ID      uint
+	Name    string
+	Profile datatypes.JSON `gorm:"jsonindex:path:age,type:integer"`
+}
+
+func (UserWithJSONIndexV2) TableName() string {
+	return "user_with_json_indices"
+}
+
+type JSONIndexPost struct {
+	ID         uint
+	Name       string
+	Attributes datatypes.JSONMap                `gorm:"jsonindex:idx_json_index_post_attributes,gin"`
+	Tags       datatypes.JSONSlice[string]      `gorm:"jsonindex:idx_json_index_post_tags,array"`
+	Meta       datatypes.JSONType[JSONPostMeta] `gorm:"jsonindex:idx_json_index_post_author,path:author,expression"`
+}
+
+type JSONPostMeta struct {
+	Author string `json:"author"`
+}
+
+// JSONIndexPostV2 changes the indexes of JSONIndexPost
+type JSONIndexPostV2 struct {
+	ID         uint
+	Name       string
+	Attributes datatypes.JSONMap                `gorm:"jsonindex:idx_json_index_post_attributes,gin:jsonb_path_ops"`
+	Tags       datatypes.JSONSlice[string]      `gorm:"jsonindex:idx_json_index_post_tags,array,type:CHAR(32)"`
+	Meta       datatypes.JSONType[JSONPostMeta] `gorm:"jsonindex:idx_json_index_post_author,path:author,expression,unique"`
+}
+
+func (JSONIndexPostV2) TableName() string {
+	return "json_index_posts"
+}
+
+func TestJSONMigratorExpressionIndex(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		DB.Migrator().DropTable(&JSONIndexPost{})
+		migrator := datatypes.NewJSONMigrator(DB)
+		for i := 0; i < 2; i++ {
+			if err := migrator.AutoMigrate(&JSONIndexPost{}); err != nil {
+				t.Fatalf("failed to migrate, got error: %v", err)
+			}
+		}
+
+		indexes := []string{"idx_json_index_post_author"}
+		switch DB.Dialector.Name() {
+		case "postgres":
+			indexes = append(indexes, "idx_json_index_post_attributes")
+		case "mysql":
+			indexes = append(indexes, "idx_json_index_post_tags")
+		}
+		for _, index := range indexes {
+			if !DB.Migrator().HasIndex(&JSONIndexPost{}, index) {
+				t.Errorf("should have index %v", index)
+			}
+		}
+
+		posts := []JSONIndexPost{
+			{Name: "json-1", Attributes: datatypes.JSONMap{"a": 1}, Tags: datatypes.JSONSlice[string]{"tag1"}, Meta: datatypes.NewJSONType(JSONPostMeta{Author: "jinzhu"})},
+			{Name: "json-2", Attributes: datatypes.JSONMap{"a": 2}, Tags: datatypes.JSONSlice[string]{"tag2"}, Meta: datatypes.NewJSONType(JSONPostMeta{Author: "jinzhu"})},
+			{Name: "json-3", Attributes: datatypes.JSONMap{"a": 3}, Tags: datatypes.JSONSlice[string]{"tag3"}, Meta: datatypes.NewJSONType(JSONPostMeta{Author: "gorm"})},
+		}
+		if err := DB.Create(&posts).Error; err != nil {
+			t.Fatalf("Failed to create posts %v", err)
+		}
+
+		query := datatypes.JSONQuery("meta").Equals("jinzhu", "author")
+		sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Where(query).Find(&[]JSONIndexPost{})
+		})
+		expression := map[string]string{
+			"sqlite":   "json_extract(`meta`,'$.author')",
+			"mysql":    "CAST(JSON_UNQUOTE(JSON_EXTRACT(`meta`,'$.author')) AS CHAR(255))",
+			"postgres": `("meta" ->> 'author')`,
+		}[DB.Dialector.Name()]
+		if !strings.Contains(sql, expression) {
+			t.Errorf("query should use indexed expression, got %v", sql)
+		}
+
+		var names []string
+		if err := DB.Model(&JSONIndexPost{}).Where(query).Order("id").Pluck("name", &names).Error; err != nil {
+			t.Fatalf("failed to find posts with indexed expression, got error %v", err)
+		}
+		AssertEqual(t, names, []string{"json-1", "json-2"})
+
+		// the changed expression index is recreated as an unique index
+		DB.Where("name = ?", "json-2").Delete(&JSONIndexPost{})
+		if err := migrator.AutoMigrate(&JSONIndexPostV2{}); err != nil {
+			t.Fatalf("failed to migrate changed indexes, got error: %v", err)
+		}
+		for _, index := range indexes {
+			if !DB.Migrator().HasIndex(&JSONIndexPostV2{}, index) {
+				t.Errorf("should have index %v", index)
+			}
+		}
+
+		duplicated := JSONIndexPostV2{Name: "json-4", Meta: datatypes.NewJSONType(JSONPostMeta{Author: "jinzhu"})}
+		if err := DB.Create(&duplicated).Error; err == nil {
+			t.Errorf("should fail to create post with duplicated author")
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package datatypes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// JSONLastIndex selects the last element of a JSON array when used as a key, e.g.
+	//
+	//	JSONQuery("attributes").Equals("tag3", "tags", JSONLastIndex)
+	JSONLastIndex = "[last]"
+	// JSONAnyIndex selects every element of a JSON array when used as a key, the condition
+	// matches if any of the elements matches, e.g.
+	//
+	//	JSONQuery("attributes").Equals("sku-1", "items", JSONAnyIndex, "sku")
+	JSONAnyIndex = "[*]"
+)
+
+// JSONIndex selects the index-th element of a JSON array when used as a key, e.g.
+//
+//	JSONQuery("attributes").Equals("tag1", "tags", JSONIndex(0))
+func JSONIndex(index int) string {
+	return "[" + strconv.Itoa(index) + "]"
+}
+
+type jsonPathKind int
+
+const (
+	jsonPathKey jsonPathKind = iota
+	jsonPathIndex
+	jsonPathLast
+	jsonPathAny
+)
+
+type jsonPathSegment struct {
+	kind  jsonPathKind
+	key   string
+	index int
+}
+
+// jsonPath is a dialect neutral JSON path made of object keys and array selectors
+type jsonPath []jsonPathSegment
+
+// parseJSONKeys parses keys given to JSONQueryExpression, every key is an object key, except array
+// selectors like `[0]`, `[last]` and `[*]`, which are also accepted as suffixes of a key, e.g. `tags[0]`
+func parseJSONKeys(keys []string) jsonPath {
+	path := make(jsonPath, 0, len(keys))
+	for _, key := range keys {
+		name, selectors := key, jsonPath{}
+		for strings.HasSuffix(name, "]") {
+			idx := strings.LastIndexByte(name, '[')
+			if idx < 0 {
+				break
+			}
+
+			segment, ok := parseJSONSelector(name[idx+1 : len(name)-1])
+			if !ok {
+				break
+			}
+			selectors = append(jsonPath{segment}, selectors...)
+			name = name[:idx]
+		}
+
+		if name != "" || len(selectors) == 0 {
+			path = append(path, jsonPathSegment{kind: jsonPathKey, key: name})
+		}
+		path = append(path, selectors...)
+	}
+	return path
+}
+
+// parseJSONPath parses a path like `orgs.orga`, `tags[0]`, `items[*].sku` or `"first name".given`,
+// an optional leading `$` is ignored
+func parseJSONPath(str string) (jsonPath, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(str), "$")
+	path := jsonPath{}
+	for first := true; s != ""; first = false {
+		switch {
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path %q: unterminated '['", str)
+			}
+			segment, ok := parseJSONSelector(s[1:end])
+			if !ok {
+				return nil, fmt.Errorf("invalid json path %q: unsupported array selector %q", str, s[:end+1])
+			}
+			path = append(path, segment)
+			s = s[end+1:]
+			continue
+		case s[0] == '.':
+			s = s[1:]
+		case !first:
+			return nil, fmt.Errorf("invalid json path %q: unexpected %q", str, s[0])
+		}
+
+		if strings.HasPrefix(s, `"`) {
+			var key strings.Builder
+			end := -1
+			for i := 1; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					key.WriteByte(s[i])
+				} else if s[i] == '"' {
+					end = i
+					break
+				} else {
+					key.WriteByte(s[i])
+				}
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path %q: unterminated '\"'", str)
+			}
+			path = append(path, jsonPathSegment{kind: jsonPathKey, key: key.String()})
+			s = s[end+1:]
+			continue
+		}
+
+		end := strings.IndexAny(s, ".[")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("invalid json path %q: empty key", str)
+		}
+		path = append(path, jsonPathSegment{kind: jsonPathKey, key: s[:end]})
+		s = s[end:]
+	}
+	return path, nil
+}
+
+// parseJSONUpdatePath parses a path given to update expressions like JSONSet, the syntax of parseJSONPath
+// and the native PostgreSQL text array syntax like `{orgs, orga}` or `{tags, 0}` are accepted
+func parseJSONUpdatePath(str string) (jsonPath, error) {
+	s := strings.TrimSpace(str)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return parseJSONPath(s)
+	}
+
+	path := jsonPath{}
+	if s = strings.TrimSpace(s[1 : len(s)-1]); s == "" {
+		return path, nil
+	}
+	for _, elem := range strings.Split(s, ",") {
+		elem = strings.TrimSpace(elem)
+		if unquoted, err := strconv.Unquote(elem); err == nil && strings.HasPrefix(elem, `"`) {
+			path = append(path, jsonPathSegment{kind: jsonPathKey, key: unquoted})
+		} else if elem == "-1" {
+			path = append(path, jsonPathSegment{kind: jsonPathLast})
+		} else if index, err := strconv.Atoi(elem); err == nil && index >= 0 {
+			path = append(path, jsonPathSegment{kind: jsonPathIndex, index: index})
+		} else if elem != "" {
+			path = append(path, jsonPathSegment{kind: jsonPathKey, key: elem})
+		} else {
+			return nil, fmt.Errorf("invalid json path %q: empty key", str)
+		}
+	}
+	return path, nil
+}
+
+func parseJSONSelector(selector string) (jsonPathSegment, bool) {
+	switch selector {
+	case "last", "#-1":
+		return jsonPathSegment{kind: jsonPathLast}, true
+	case "*":
+		return jsonPathSegment{kind: jsonPathAny}, true
+	}
+
+	index, err := strconv.Atoi(selector)
+	if err != nil || index < 0 {
+		return jsonPathSegment{}, false
+	}
+	return jsonPathSegment{kind: jsonPathIndex, index: index}, true
+}
+
+// sql returns the path in the syntax of MySQL, SQLite or SQL Server, e.g. `$.orgs."org a"[0]`
+func (path jsonPath) sql(dialect string) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, segment := range path {
+		switch segment.kind {
+		case jsonPathKey:
+			b.WriteByte('.')
+			if isJSONIdentifier(segment.key) {
+				b.WriteString(segment.key)
+			} else {
+				b.WriteString(quoteJSONKey(segment.key))
+			}
+		case jsonPathIndex:
+			b.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case jsonPathLast:
+			if dialect == "sqlite" {
+				b.WriteString("[#-1]")
+			} else {
+				b.WriteString("[last]")
+			}
+		case jsonPathAny:
+			b.WriteString("[*]")
+		}
+	}
+	return b.String()
+}
+
+// literal returns the MySQL path as string literal, for arguments that don't accept parameters like the
+// path of JSON_TABLE
+func (path jsonPath) literal() string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(path.sql("mysql")) + "'"
+}
+
+// jsonpath returns the path in the syntax of PostgreSQL jsonpath, e.g. `$."orgs"."org a"[0]`
+func (path jsonPath) jsonpath() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, segment := range path {
+		switch segment.kind {
+		case jsonPathKey:
+			b.WriteByte('.')
+			b.WriteString(quoteJSONKey(segment.key))
+		case jsonPathIndex:
+			b.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case jsonPathLast:
+			b.WriteString("[last]")
+		case jsonPathAny:
+			b.WriteString("[*]")
+		}
+	}
+	return b.String()
+}
+
+// keys returns the path as PostgreSQL path elements, e.g. `orgs`, `org a`, `0`
+func (path jsonPath) keys() []string {
+	keys := make([]string, 0, len(path))
+	for _, segment := range path {
+		switch segment.kind {
+		case jsonPathKey:
+			keys = append(keys, segment.key)
+		case jsonPathIndex:
+			keys = append(keys, strconv.Itoa(segment.index))
+		case jsonPathLast:
+			keys = append(keys, "-1")
+		}
+	}
+	return keys
+}
+
+// array returns the path as PostgreSQL text array, e.g. `{orgs,"org a",0}`
+func (path jsonPath) array() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for idx, key := range path.keys() {
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+		if key == "" || strings.EqualFold(key, "null") || strings.ContainsAny(key, "{},\"\\ \t\n\r") {
+			b.WriteString(quoteJSONKey(key))
+		} else {
+			b.WriteString(key)
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// wildcards returns the positions of JSONAnyIndex segments
+func (path jsonPath) wildcards() []int {
+	var idxes []int
+	for idx, segment := range path {
+		if segment.kind == jsonPathAny {
+			idxes = append(idxes, idx)
+		}
+	}
+	return idxes
+}
+
+func (path jsonPath) has(kind jsonPathKind) bool {
+	for _, segment := range path {
+		if segment.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONKeysPath returns keys as a path like `orgs.orga`, which can be used with JSONQuery("attributes").Extract
+// and JSONSet, keys that are not identifiers are quoted, e.g. `orgs."org a"`
+func JSONKeysPath(keys ...string) string {
+	quoted := make([]string, len(keys))
+	for idx, key := range keys {
+		if isJSONIdentifier(key) {
+			quoted[idx] = key
+		} else {
+			quoted[idx] = quoteJSONKey(key)
+		}
+	}
+	return strings.Join(quoted, ".")
+}
+
+func isJSONIdentifier(key string) bool {
+	for idx, r := range key {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (idx == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return key != ""
+}
+
+func quoteJSONKey(key string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`
+}
+
+// parseStmtJSONUpdatePath parses a path given to update expressions like JSONSet, errors are added to stmt
+func parseStmtJSONUpdatePath(stmt *gorm.Statement, str string) (jsonPath, bool) {
+	path, err := parseJSONUpdatePath(str)
+	switch {
+	case err != nil:
+	case path.has(jsonPathAny):
+		err = fmt.Errorf("invalid json path %q: wildcards are not supported by updates", str)
+	case path.has(jsonPathLast) && stmt.Dialector.Name() == "sqlserver":
+		err = fmt.Errorf("json path selector [last] is not supported by sqlserver")
+	}
+	if err != nil {
+		_ = stmt.AddError(err)
+		return nil, false
+	}
+	return path, true
+}
+
+// jsonUpdatePath returns the path given to update expressions like JSONSet in the syntax of the dialect
+func jsonUpdatePath(stmt *gorm.Statement, str string) string {
+	path, ok := parseStmtJSONUpdatePath(stmt, str)
+	if !ok {
+		return str
+	}
+
+	if stmt.Dialector.Name() == "postgres" {
+		return path.array()
+	}
+	return path.sql(stmt.Dialector.Name())
+}
+
+// jsonSource is the json document a path is applied to, either the column, or the current element
+// of the table function named alias that expands the wildcards of a path
+type jsonSource struct {
+	column string
+	alias  string
+}
+
+// writeJSONSource writes the json document of src, in SQLite the document of an element is still the
+// column, the element is selected by its path
+func writeJSONSource(stmt *gorm.Statement, src jsonSource) {
+	switch {
+	case src.alias == "" || stmt.Dialector.Name() == "sqlite":
+		stmt.WriteQuoted(src.column)
+	case stmt.Dialector.Name() == "sqlserver":
+		stmt.WriteString("CASE WHEN " + src.alias + ".[type] IN (4,5) THEN " + src.alias + ".[value] END")
+	default:
+		stmt.WriteString(src.alias + ".value")
+	}
+}
+
+// writeJSONPathArg writes path relative to src as argument of functions like JSON_EXTRACT
+func writeJSONPathArg(stmt *gorm.Statement, src jsonSource, path jsonPath) {
+	if src.alias != "" && stmt.Dialector.Name() == "sqlite" {
+		stmt.WriteString(src.alias + ".fullkey")
+		if len(path) > 0 {
+			stmt.WriteString(" || ")
+			stmt.AddVar(stmt, strings.TrimPrefix(path.sql("sqlite"), "$"))
+		}
+		return
+	}
+	stmt.AddVar(stmt, path.sql(stmt.Dialector.Name()))
+}
+
+// writeJSONEach writes the condition built by leaf for path, wildcards of path are expanded with
+// table functions in an EXISTS subquery, so the condition matches if any of the elements matches
+func writeJSONEach(stmt *gorm.Statement, src jsonSource, path jsonPath, leaf func(src jsonSource, path jsonPath)) {
+	if stmt.Dialector.Name() == "sqlserver" && path.has(jsonPathLast) {
+		_ = stmt.AddError(errors.New("json path selector [last] is not supported by sqlserver"))
+		return
+	}
+
+	if len(path.wildcards()) == 0 {
+		leaf(src, path)
+		return
+	}
+
+	stmt.WriteString("EXISTS(SELECT 1")
+	src, path = writeJSONEachFrom(stmt, src, path)
+	stmt.WriteString(" WHERE ")
+	leaf(src, path)
+	stmt.WriteByte(')')
+}
+
+// jsonEachSource returns the source and the remaining path of the elements expanded by writeJSONEachFrom
+func jsonEachSource(dialect string, src jsonSource, path jsonPath) (jsonSource, jsonPath) {
+	wildcards := path.wildcards()
+	alias := "j1"
+	if dialect == "sqlite" || dialect == "sqlserver" {
+		alias = "j" + strconv.Itoa(len(wildcards))
+	}
+	return jsonSource{column: src.column, alias: alias}, path[wildcards[len(wildcards)-1]+1:]
+}
+
+// writeJSONEachFrom writes the FROM clause expanding the wildcards of path, MySQL and PostgreSQL
+// expand all wildcards with one table function, SQLite and SQL Server use one table function per wildcard
+func writeJSONEachFrom(stmt *gorm.Statement, src jsonSource, path jsonPath) (jsonSource, jsonPath) {
+	wildcards := path.wildcards()
+	last := wildcards[len(wildcards)-1]
+
+	stmt.WriteString(" FROM ")
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_TABLE(")
+		writeJSONSource(stmt, src)
+		stmt.WriteString("," + path[:last+1].literal() + " COLUMNS(value JSON PATH '$')) AS j1")
+	case "postgres":
+		stmt.WriteString("jsonb_path_query(")
+		writeJSONSource(stmt, src)
+		stmt.WriteString("::jsonb,")
+		stmt.AddVar(stmt, path[:last+1].jsonpath())
+		stmt.WriteString("::jsonpath) AS j1(value)")
+	default:
+		start, each := 0, src
+		for idx, wildcard := range wildcards {
+			if idx > 0 {
+				if stmt.Dialector.Name() == "sqlserver" {
+					stmt.WriteString(" CROSS APPLY ")
+				} else {
+					stmt.WriteString(", ")
+				}
+			}
+
+			if stmt.Dialector.Name() == "sqlserver" {
+				stmt.WriteString("OPENJSON(")
+			} else {
+				stmt.WriteString("json_each(")
+			}
+			writeJSONSource(stmt, each)
+			stmt.WriteByte(',')
+			writeJSONPathArg(stmt, each, path[start:wildcard])
+
+			each = jsonSource{column: src.column, alias: "j" + strconv.Itoa(idx+1)}
+			stmt.WriteString(") AS " + each.alias)
+			start = wildcard + 1
+		}
+	}
+	return jsonEachSource(stmt.Dialector.Name(), src, path)
+}
@@ -0,0 +1,745 @@
+package datatypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JSONPathQueryExpression json path query expression, filters rows with SQL/JSON path (jsonpath) expressions,
+// implements clause.Expression interface to use as querier
+type JSONPathQueryExpression struct {
+	column     string
+	conditions []jsonPathCondition
+}
+
+type jsonPathCondition struct {
+	match bool
+	path  string
+	vars  map[string]interface{}
+}
+
+// JSONPathQuery query column with SQL/JSON path expressions, PostgreSQL evaluates the expressions natively,
+// SQLite and MySQL emulate a subset of them with json_each and JSON_TABLE, e.g.
+//
+//	JSONPathQuery("attributes").Exists(`$.items[*] ? (@.qty > $min)`, map[string]interface{}{"min": 5})
+//
+// the emulation supports key, [n], [last] and [*] accessors, filters with ==, !=, <>, <, <=, >, >=, &&, ||, !,
+// exists() and is unknown, compared with literals and variables, arrays are not unwrapped implicitly
+// like the lax mode of PostgreSQL, use [*] to select their elements
+func JSONPathQuery(column string) *JSONPathQueryExpression {
+	return &JSONPathQueryExpression{column: column}
+}
+
+// Exists checks if path returns any item for the json of the column, vars are bound to the $name variables of path,
+// uses the index friendly @? operator in PostgreSQL when there are no vars
+func (jsonQuery *JSONPathQueryExpression) Exists(path string, vars map[string]interface{}) *JSONPathQueryExpression {
+	jsonQuery.conditions = append(jsonQuery.conditions, jsonPathCondition{path: path, vars: vars})
+	return jsonQuery
+}
+
+// Match checks if the predicate path is true for the json of the column, e.g. `$.age >= $min && exists($.orgs)`,
+// vars are bound to the $name variables of path, uses the index friendly @@ operator in PostgreSQL when there are no vars
+func (jsonQuery *JSONPathQueryExpression) Match(path string, vars map[string]interface{}) *JSONPathQueryExpression {
+	jsonQuery.conditions = append(jsonQuery.conditions, jsonPathCondition{match: true, path: path, vars: vars})
+	return jsonQuery
+}
+
+// Build implements clause.Expression
+func (jsonQuery *JSONPathQueryExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	if len(jsonQuery.conditions) > 1 {
+		stmt.WriteByte('(')
+	}
+	for idx, cond := range jsonQuery.conditions {
+		if idx > 0 {
+			stmt.WriteString(" AND ")
+		}
+
+		switch stmt.Dialector.Name() {
+		case "postgres":
+			jsonQuery.buildPostgres(stmt, cond)
+		case "mysql", "sqlite":
+			parser := &jsonPathParser{str: cond.path, vars: cond.vars}
+			if cond.match {
+				pred, err := parser.parsePredicateQuery()
+				if err != nil {
+					_ = stmt.AddError(err)
+					return
+				}
+				(&jsonPathBuilder{stmt: stmt, column: jsonQuery.column}).predicate(jsonSource{column: jsonQuery.column}, pred)
+			} else {
+				expr, err := parser.parsePathQuery()
+				if err != nil {
+					_ = stmt.AddError(err)
+					return
+				}
+				(&jsonPathBuilder{stmt: stmt, column: jsonQuery.column}).exists(jsonSource{column: jsonQuery.column}, expr)
+			}
+		default:
+			_ = stmt.AddError(fmt.Errorf("json path query %q is not supported by %s", cond.path, stmt.Dialector.Name()))
+			return
+		}
+	}
+	if len(jsonQuery.conditions) > 1 {
+		stmt.WriteByte(')')
+	}
+}
+
+func (jsonQuery *JSONPathQueryExpression) buildPostgres(stmt *gorm.Statement, cond jsonPathCondition) {
+	if len(cond.vars) == 0 {
+		stmt.WriteQuoted(jsonQuery.column)
+		if cond.match {
+			stmt.WriteString("::jsonb @@ ")
+		} else {
+			stmt.WriteString("::jsonb @? ")
+		}
+		stmt.AddVar(stmt, cond.path)
+		stmt.WriteString("::jsonpath")
+		return
+	}
+
+	vars, err := json.Marshal(cond.vars)
+	if err != nil {
+		_ = stmt.AddError(err)
+		return
+	}
+
+	if cond.match {
+		stmt.WriteString("jsonb_path_match(")
+	} else {
+		stmt.WriteString("jsonb_path_exists(")
+	}
+	stmt.WriteQuoted(jsonQuery.column)
+	stmt.WriteString("::jsonb,")
+	stmt.AddVar(stmt, cond.path)
+	stmt.WriteString("::jsonpath,")
+	stmt.AddVar(stmt, string(vars))
+	stmt.WriteString("::jsonb,true)")
+}
+
+// jsonPathExpr is a parsed jsonpath like `$.items[*] ? (@.qty > 5).sku`, relative to the root document
+// for `$` or to the current item of the enclosing filter for `@`
+type jsonPathExpr struct {
+	root  string
+	steps []jsonPathStep
+}
+
+// jsonPathStep is either an accessor segment or a filter applied to the current item
+type jsonPathStep struct {
+	segment jsonPathSegment
+	filter  *jsonPathPredicate
+}
+
+// jsonPathPredicate is a jsonpath predicate, op is one of `&&`, `||`, `!`, `is unknown`, `exists` or the SQL
+// operator of a comparison, comparisons are normalized to `path op value`
+type jsonPathPredicate struct {
+	op    string
+	args  []*jsonPathPredicate
+	path  *jsonPathExpr
+	value interface{}
+}
+
+type jsonPathToken struct {
+	kind byte // 'i' identifier, 's' string, 'n' number, 'p' punctuation, 0 end
+	text string
+}
+
+// jsonPathParser parses the subset of jsonpath emulated on SQLite and MySQL, variables are resolved from vars
+type jsonPathParser struct {
+	str    string
+	vars   map[string]interface{}
+	tokens []jsonPathToken
+	pos    int
+}
+
+func (parser *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid json path query %q: %s", parser.str, fmt.Sprintf(format, args...))
+}
+
+func (parser *jsonPathParser) tokenize() error {
+	s := parser.str
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return parser.errorf("unterminated string")
+			}
+			var str string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &str); err != nil {
+				return parser.errorf("invalid string %s", s[i:end+1])
+			}
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 's', text: str})
+			i = end + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			end := i + 1
+			for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || s[end] == 'e' || s[end] == 'E' ||
+				(s[end] == '+' || s[end] == '-') && (s[end-1] == 'e' || s[end-1] == 'E')) {
+				end++
+			}
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 'n', text: s[i:end]})
+			i = end
+		case c == '$' && i+1 < len(s) && (s[i+1] == '"' || isJSONPathIdentifier(s[i+1], true)):
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 'p', text: "$"})
+			i++
+			if s[i] == '"' {
+				continue
+			}
+			end := i
+			for end < len(s) && isJSONPathIdentifier(s[end], end == i) {
+				end++
+			}
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 'i', text: s[i:end]})
+			i = end
+		case isJSONPathIdentifier(c, true):
+			end := i
+			for end < len(s) && isJSONPathIdentifier(s[end], end == i) {
+				end++
+			}
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 'i', text: s[i:end]})
+			i = end
+		default:
+			op := string(c)
+			for _, punct := range []string{"==", "!=", "<>", "<=", ">=", "&&", "||"} {
+				if strings.HasPrefix(s[i:], punct) {
+					op = punct
+				}
+			}
+			if !strings.Contains("$@.[]*()?!<>=&|", string(c)) || op == "=" || op == "&" || op == "|" {
+				return parser.errorf("unexpected %q", op)
+			}
+			parser.tokens = append(parser.tokens, jsonPathToken{kind: 'p', text: op})
+			i += len(op)
+		}
+	}
+	return nil
+}
+
+func isJSONPathIdentifier(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 || !first && c >= '0' && c <= '9'
+}
+
+func (parser *jsonPathParser) peek() jsonPathToken {
+	if parser.pos < len(parser.tokens) {
+		return parser.tokens[parser.pos]
+	}
+	return jsonPathToken{}
+}
+
+func (parser *jsonPathParser) next() jsonPathToken {
+	token := parser.peek()
+	if parser.pos < len(parser.tokens) {
+		parser.pos++
+	}
+	return token
+}
+
+func (parser *jsonPathParser) accept(kind byte, text string) bool {
+	if token := parser.peek(); token.kind == kind && token.text == text {
+		parser.pos++
+		return true
+	}
+	return false
+}
+
+func (parser *jsonPathParser) expect(text string) error {
+	if !parser.accept('p', text) {
+		return parser.errorf("expected %q", text)
+	}
+	return nil
+}
+
+// begin tokenizes the query and skips the lax mode prefix
+func (parser *jsonPathParser) begin() error {
+	if err := parser.tokenize(); err != nil {
+		return err
+	}
+	if parser.accept('i', "strict") {
+		return parser.errorf("strict mode is not supported")
+	}
+	parser.accept('i', "lax")
+	return nil
+}
+
+func (parser *jsonPathParser) end() error {
+	if token := parser.peek(); token.kind != 0 {
+		return parser.errorf("unsupported %q", token.text)
+	}
+	return nil
+}
+
+func (parser *jsonPathParser) parsePathQuery() (*jsonPathExpr, error) {
+	if err := parser.begin(); err != nil {
+		return nil, err
+	}
+	expr, err := parser.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil || expr.root != "$" {
+		return nil, parser.errorf("path must start with $")
+	}
+	return expr, parser.end()
+}
+
+func (parser *jsonPathParser) parsePredicateQuery() (*jsonPathPredicate, error) {
+	if err := parser.begin(); err != nil {
+		return nil, err
+	}
+	pred, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return pred, parser.end()
+}
+
+// parsePath parses a path starting with `$` or `@`, returns nil if the next token doesn't start a path
+func (parser *jsonPathParser) parsePath() (*jsonPathExpr, error) {
+	token := parser.peek()
+	if token.kind != 'p' || token.text != "$" && token.text != "@" {
+		return nil, nil
+	}
+	if next := parser.tokens[parser.pos+1:]; token.text == "$" && len(next) > 0 && (next[0].kind == 'i' || next[0].kind == 's') {
+		return nil, nil // variable
+	}
+	parser.pos++
+
+	expr := &jsonPathExpr{root: token.text}
+	for {
+		switch {
+		case parser.accept('p', "."):
+			token := parser.next()
+			if token.kind != 'i' && token.kind != 's' {
+				return nil, parser.errorf("unsupported accessor .%s", token.text)
+			}
+			if parser.peek().text == "(" {
+				return nil, parser.errorf("unsupported method .%s()", token.text)
+			}
+			expr.steps = append(expr.steps, jsonPathStep{segment: jsonPathSegment{kind: jsonPathKey, key: token.text}})
+		case parser.accept('p', "["):
+			token := parser.next()
+			var segment jsonPathSegment
+			switch {
+			case token.kind == 'p' && token.text == "*":
+				segment.kind = jsonPathAny
+			case token.kind == 'i' && token.text == "last":
+				segment.kind = jsonPathLast
+			case token.kind == 'n':
+				index, err := strconv.Atoi(token.text)
+				if err != nil || index < 0 {
+					return nil, parser.errorf("unsupported array index %s", token.text)
+				}
+				segment = jsonPathSegment{kind: jsonPathIndex, index: index}
+			default:
+				return nil, parser.errorf("unsupported array accessor [%s", token.text)
+			}
+			if err := parser.expect("]"); err != nil {
+				return nil, err
+			}
+			expr.steps = append(expr.steps, jsonPathStep{segment: segment})
+		case parser.accept('p', "?"):
+			if err := parser.expect("("); err != nil {
+				return nil, err
+			}
+			pred, err := parser.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := parser.expect(")"); err != nil {
+				return nil, err
+			}
+			expr.steps = append(expr.steps, jsonPathStep{filter: pred})
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (parser *jsonPathParser) parseOr() (*jsonPathPredicate, error) {
+	pred, err := parser.parseAnd()
+	for err == nil && parser.accept('p', "||") {
+		var right *jsonPathPredicate
+		if right, err = parser.parseAnd(); err == nil {
+			pred = &jsonPathPredicate{op: "||", args: []*jsonPathPredicate{pred, right}}
+		}
+	}
+	return pred, err
+}
+
+func (parser *jsonPathParser) parseAnd() (*jsonPathPredicate, error) {
+	pred, err := parser.parseUnary()
+	for err == nil && parser.accept('p', "&&") {
+		var right *jsonPathPredicate
+		if right, err = parser.parseUnary(); err == nil {
+			pred = &jsonPathPredicate{op: "&&", args: []*jsonPathPredicate{pred, right}}
+		}
+	}
+	return pred, err
+}
+
+func (parser *jsonPathParser) parseUnary() (*jsonPathPredicate, error) {
+	switch {
+	case parser.accept('p', "!"):
+		if err := parser.expect("("); err != nil {
+			return nil, err
+		}
+		pred, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathPredicate{op: "!", args: []*jsonPathPredicate{pred}}, parser.expect(")")
+	case parser.accept('p', "("):
+		pred, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := parser.expect(")"); err != nil {
+			return nil, err
+		}
+		if parser.accept('i', "is") {
+			if !parser.accept('i', "unknown") {
+				return nil, parser.errorf("expected \"unknown\"")
+			}
+			pred = &jsonPathPredicate{op: "is unknown", args: []*jsonPathPredicate{pred}}
+		}
+		return pred, nil
+	case parser.accept('i', "exists"):
+		if err := parser.expect("("); err != nil {
+			return nil, err
+		}
+		path, err := parser.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if path == nil {
+			return nil, parser.errorf("exists requires a path")
+		}
+		return &jsonPathPredicate{op: "exists", path: path}, parser.expect(")")
+	}
+	return parser.parseComparison()
+}
+
+var (
+	jsonPathOperators        = map[string]string{"==": "=", "!=": "<>", "<>": "<>", "<": "<", "<=": "<=", ">": ">", ">=": ">="}
+	jsonPathFlippedOperators = map[string]string{"=": "=", "<>": "<>", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+)
+
+func (parser *jsonPathParser) parseComparison() (*jsonPathPredicate, error) {
+	leftPath, leftValue, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	token := parser.next()
+	op, ok := jsonPathOperators[token.text]
+	if token.kind != 'p' || !ok {
+		return nil, parser.errorf("unsupported %q, expected a comparison", token.text)
+	}
+
+	rightPath, rightValue, err := parser.parseOperand()
+	switch {
+	case err != nil:
+		return nil, err
+	case leftPath != nil && rightPath == nil:
+		return &jsonPathPredicate{op: op, path: leftPath, value: rightValue}, nil
+	case leftPath == nil && rightPath != nil:
+		return &jsonPathPredicate{op: jsonPathFlippedOperators[op], path: rightPath, value: leftValue}, nil
+	}
+	return nil, parser.errorf("comparisons require a path and a value")
+}
+
+// parseOperand parses a path, a literal or a variable
+func (parser *jsonPathParser) parseOperand() (*jsonPathExpr, interface{}, error) {
+	if path, err := parser.parsePath(); path != nil || err != nil {
+		return path, nil, err
+	}
+
+	token := parser.next()
+	switch {
+	case token.kind == 's':
+		return nil, token.text, nil
+	case token.kind == 'n':
+		return nil, jsonPathNumber(json.Number(token.text)), nil
+	case token.kind == 'i' && token.text == "true":
+		return nil, true, nil
+	case token.kind == 'i' && token.text == "false":
+		return nil, false, nil
+	case token.kind == 'i' && token.text == "null":
+		return nil, nil, nil
+	case token.kind == 'p' && token.text == "$":
+		name := parser.next()
+		if name.kind != 'i' && name.kind != 's' {
+			return nil, nil, parser.errorf("invalid variable")
+		}
+		value, ok := parser.vars[name.text]
+		if !ok {
+			return nil, nil, parser.errorf("variable $%s is not defined", name.text)
+		}
+		if value = jsonPathValue(value); value == jsonPathUnsupported {
+			return nil, nil, parser.errorf("variable $%s must be a string, number, bool or nil", name.text)
+		}
+		return nil, value, nil
+	}
+	return nil, nil, parser.errorf("unsupported operand %q", token.text)
+}
+
+var jsonPathUnsupported = &struct{}{}
+
+// jsonPathValue converts value of a variable to string, int64, float64, bool or nil
+func jsonPathValue(value interface{}) interface{} {
+	if number, ok := value.(json.Number); ok {
+		return jsonPathNumber(number)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return jsonPathUnsupported
+}
+
+func jsonPathNumber(number json.Number) interface{} {
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+// jsonPathBuilder emulates jsonpath expressions in SQLite and MySQL, wildcards are expanded with json_each
+// and JSON_TABLE in EXISTS subqueries, predicates follow the three-valued logic of jsonpath, comparisons of
+// values with different types are unknown (NULL), comparisons with missing values are false
+type jsonPathBuilder struct {
+	stmt    *gorm.Statement
+	column  string
+	aliases int
+}
+
+func (builder *jsonPathBuilder) source(current jsonSource, expr *jsonPathExpr) jsonSource {
+	if expr.root == "$" {
+		return jsonSource{column: builder.column}
+	}
+	return current
+}
+
+// exists writes the condition that expr returns any item
+func (builder *jsonPathBuilder) exists(current jsonSource, expr *jsonPathExpr) {
+	builder.walk(builder.source(current, expr), jsonPath{}, expr.steps, func(src jsonSource, path jsonPath) {
+		if builder.stmt.Dialector.Name() == "mysql" {
+			builder.stmt.WriteString("JSON_CONTAINS_PATH(")
+			writeJSONSource(builder.stmt, src)
+			builder.stmt.WriteString(",'one',")
+			writeJSONPathArg(builder.stmt, src, path)
+			builder.stmt.WriteByte(')')
+		} else {
+			builder.writeType(src, path)
+			builder.stmt.WriteString(" IS NOT NULL")
+		}
+	})
+}
+
+// walk writes the condition built by leaf for the items selected by steps, starting at path of src
+func (builder *jsonPathBuilder) walk(src jsonSource, path jsonPath, steps []jsonPathStep, leaf func(src jsonSource, path jsonPath)) {
+	stmt := builder.stmt
+	for idx, step := range steps {
+		switch {
+		case step.filter != nil:
+			stmt.WriteByte('(')
+			builder.predicate(src, builder.relative(path, step.filter))
+			stmt.WriteString(" AND ")
+			builder.walk(src, path, steps[idx+1:], leaf)
+			stmt.WriteByte(')')
+			return
+		case step.segment.kind == jsonPathAny:
+			builder.aliases++
+			each := jsonSource{column: src.column, alias: "p" + strconv.Itoa(builder.aliases)}
+
+			stmt.WriteString("EXISTS(SELECT 1 FROM ")
+			if stmt.Dialector.Name() == "mysql" {
+				stmt.WriteString("JSON_TABLE(")
+				writeJSONSource(stmt, src)
+				stmt.WriteString("," + append(path[:len(path):len(path)], step.segment).literal() + " COLUMNS(value JSON PATH '$')) AS " + each.alias)
+			} else {
+				stmt.WriteString("json_each(")
+				writeJSONSource(stmt, src)
+				stmt.WriteByte(',')
+				writeJSONPathArg(stmt, src, path)
+				stmt.WriteString(") AS " + each.alias)
+			}
+			stmt.WriteString(" WHERE ")
+			builder.walk(each, jsonPath{}, steps[idx+1:], leaf)
+			stmt.WriteByte(')')
+			return
+		default:
+			path = append(path[:len(path):len(path)], step.segment)
+		}
+	}
+	leaf(src, path)
+}
+
+// relative rewrites the `@` paths of pred to start at path, the current item of a filter
+func (builder *jsonPathBuilder) relative(path jsonPath, pred *jsonPathPredicate) *jsonPathPredicate {
+	if len(path) == 0 {
+		return pred
+	}
+
+	relative := *pred
+	if pred.path != nil && pred.path.root == "@" {
+		steps := make([]jsonPathStep, 0, len(path)+len(pred.path.steps))
+		for _, segment := range path {
+			steps = append(steps, jsonPathStep{segment: segment})
+		}
+		relative.path = &jsonPathExpr{root: "@", steps: append(steps, pred.path.steps...)}
+	}
+	relative.args = make([]*jsonPathPredicate, len(pred.args))
+	for idx, arg := range pred.args {
+		relative.args[idx] = builder.relative(path, arg)
+	}
+	return &relative
+}
+
+// predicate writes pred for the current item
+func (builder *jsonPathBuilder) predicate(current jsonSource, pred *jsonPathPredicate) {
+	stmt := builder.stmt
+	switch pred.op {
+	case "&&", "||":
+		stmt.WriteByte('(')
+		builder.predicate(current, pred.args[0])
+		if pred.op == "&&" {
+			stmt.WriteString(" AND ")
+		} else {
+			stmt.WriteString(" OR ")
+		}
+		builder.predicate(current, pred.args[1])
+		stmt.WriteByte(')')
+	case "!":
+		stmt.WriteString("NOT (")
+		builder.predicate(current, pred.args[0])
+		stmt.WriteByte(')')
+	case "is unknown":
+		stmt.WriteByte('(')
+		builder.predicate(current, pred.args[0])
+		stmt.WriteString(") IS NULL")
+	case "exists":
+		builder.exists(current, pred.path)
+	default:
+		builder.walk(builder.source(current, pred.path), jsonPath{}, pred.path.steps, func(src jsonSource, path jsonPath) {
+			builder.compare(src, path, pred.op, pred.value)
+		})
+	}
+}
+
+// compare writes the comparison of the value at path with value, false if the value is missing,
+// unknown if the types don't match
+func (builder *jsonPathBuilder) compare(src jsonSource, path jsonPath, op string, value interface{}) {
+	stmt, mysql := builder.stmt, builder.stmt.Dialector.Name() == "mysql"
+	stmt.WriteString("CASE WHEN ")
+	builder.writeType(src, path)
+	stmt.WriteString(" IS NULL THEN 0")
+
+	if value == nil {
+		if op != "=" && op != "<>" {
+			_ = stmt.AddError(errors.New("json path query only supports == and != comparisons with null"))
+		}
+		stmt.WriteString(" ELSE ")
+		builder.writeType(src, path)
+		if mysql {
+			stmt.WriteString(" " + op + " 'NULL' END")
+		} else {
+			stmt.WriteString(" " + op + " 'null' END")
+		}
+		return
+	}
+
+	// MySQL compares strings and booleans unquoted, SQLite extracts booleans as 1 and 0
+	unquote := false
+	stmt.WriteString(" WHEN ")
+	builder.writeType(src, path)
+	switch v := value.(type) {
+	case string:
+		unquote = mysql
+		if mysql {
+			stmt.WriteString(" = 'STRING'")
+		} else {
+			stmt.WriteString(" = 'text'")
+		}
+	case bool:
+		if mysql {
+			unquote, value = true, strconv.FormatBool(v)
+			stmt.WriteString(" = 'BOOLEAN'")
+		} else {
+			stmt.WriteString(" IN ('true','false')")
+		}
+	default:
+		if mysql {
+			stmt.WriteString(" IN ('INTEGER','UNSIGNED INTEGER','DOUBLE','DECIMAL')")
+		} else {
+			stmt.WriteString(" IN ('integer','real')")
+		}
+	}
+
+	stmt.WriteString(" THEN ")
+	if unquote {
+		stmt.WriteString("JSON_UNQUOTE(")
+	}
+	stmt.WriteString("JSON_EXTRACT(")
+	writeJSONSource(stmt, src)
+	stmt.WriteByte(',')
+	writeJSONPathArg(stmt, src, path)
+	stmt.WriteByte(')')
+	if unquote {
+		stmt.WriteByte(')')
+	}
+	stmt.WriteString(" " + op + " ")
+	stmt.AddVar(stmt, value)
+	stmt.WriteString(" END")
+}
+
+// writeType writes the json type of the value at path, NULL if it is missing
+func (builder *jsonPathBuilder) writeType(src jsonSource, path jsonPath) {
+	stmt := builder.stmt
+	if stmt.Dialector.Name() == "mysql" {
+		stmt.WriteString("JSON_TYPE(JSON_EXTRACT(")
+		writeJSONSource(stmt, src)
+		stmt.WriteByte(',')
+		writeJSONPathArg(stmt, src, path)
+		stmt.WriteString("))")
+		return
+	}
+
+	stmt.WriteString("json_type(")
+	writeJSONSource(stmt, src)
+	stmt.WriteByte(',')
+	writeJSONPathArg(stmt, src, path)
+	stmt.WriteByte(')')
+}
@@ -0,0 +1,105 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONPathQuery(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"age": 18, "active": true, "tags": ["tag1", "tag2"], "items": [{"sku": "sku-1", "qty": 3}, {"sku": "sku-2", "qty": 8}]}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"age": "28", "active": false, "tags": ["tag3"], "items": [{"sku": "sku-3", "qty": 5, "tags": ["gift"]}]}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"age": 40, "orgs": {"orga": "orga"}, "items": []}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONPathQueryExpression
+			expect []string
+		}{
+			{
+				name:   "exists path",
+				query:  datatypes.JSONPathQuery("attributes").Exists(`$.orgs.orga`, nil),
+				expect: []string{"json-3"},
+			}, {
+				name:   "exists filter",
+				query:  datatypes.JSONPathQuery("attributes").Exists(`$.items[*] ? (@.qty > 5)`, nil),
+				expect: []string{"json-1"},
+			}, {
+				name:   "exists filter with vars",
+				query:  datatypes.JSONPathQuery("attributes").Exists(`$.items[*] ? (@.qty >= $min && @.sku != $sku)`, map[string]interface{}{"min": 5, "sku": "sku-2"}),
+				expect: []string{"json-2"},
+			}, {
+				name:   "exists nested filter",
+				query:  datatypes.JSONPathQuery("attributes").Exists(`$.items[*] ? (exists(@.tags[*] ? (@ == "gift"))).sku`, nil),
+				expect: []string{"json-2"},
+			}, {
+				name:   "match types",
+				query:  datatypes.JSONPathQuery("attributes").Match(`$.age > 20`, nil),
+				expect: []string{"json-3"},
+			}, {
+				name:   "match bool",
+				query:  datatypes.JSONPathQuery("attributes").Match(`$.active == true || $.tags[*] == "tag3"`, nil),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "match not",
+				query:  datatypes.JSONPathQuery("attributes").Match(`!(exists($.orgs))`, nil),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "match unknown",
+				query:  datatypes.JSONPathQuery("attributes").Match(`($.age > 20) is unknown`, nil),
+				expect: []string{"json-2"},
+			}, {
+				name:   "conditions",
+				query:  datatypes.JSONPathQuery("attributes").Exists(`$.items[*]`, nil).Match(`$.age < $max`, map[string]interface{}{"max": 30}),
+				expect: []string{"json-1"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json path, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+
+		if SupportedDriver("sqlite", "mysql") {
+			var results []UserWithJSON
+			if err := DB.Where(datatypes.JSONPathQuery("attributes").Exists(`$.items.size()`, nil)).Find(&results).Error; err == nil {
+				t.Errorf("should fail to emulate unsupported json path")
+			}
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package datatypes
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JSONTableExpression json table expression, implements clause.Expression interface to expand the
+// objects of a json array into rows with typed columns
+type JSONTableExpression struct {
+	column  string
+	alias   string
+	path    string
+	columns []jsonTableColumn
+}
+
+type jsonTableColumn struct {
+	key  string
+	kind string
+}
+
+// JSONTable expand the objects of the json array in column into rows named alias, the columns of the rows
+// are the keys of the objects declared by Text, Int, Float, Bool and JSON
+//
+//	items := JSONTable("orders.items", "items").Text("sku").Int("qty").Float("price")
+//	DB.Table("orders").Joins("?", items.CrossJoin()).
+//		Select("orders.id, ? AS sku, ? AS qty", items.Field("sku"), items.Field("qty")).
+//		Where("? > ?", items.Field("qty"), 1).Scan(&lines)
+//
+// it is implemented with JSON_TABLE in MySQL, jsonb_to_recordset in PostgreSQL, OPENJSON in SQL Server and
+// json_each in SQLite, SQLite can't name the columns of json_each, so columns should be referenced by Field
+func JSONTable(column, alias string) *JSONTableExpression {
+	return &JSONTableExpression{column: column, alias: alias}
+}
+
+// Path expand the array at path of the column, path is the same as JSONQueryExpression.Extract
+func (jsonTable *JSONTableExpression) Path(path string) *JSONTableExpression {
+	jsonTable.path = path
+	return jsonTable
+}
+
+// Text declare text columns for keys, strings are unquoted
+func (jsonTable *JSONTableExpression) Text(keys ...string) *JSONTableExpression {
+	return jsonTable.add("text", keys)
+}
+
+// Int declare integer columns for keys
+func (jsonTable *JSONTableExpression) Int(keys ...string) *JSONTableExpression {
+	return jsonTable.add("int", keys)
+}
+
+// Float declare float columns for keys
+func (jsonTable *JSONTableExpression) Float(keys ...string) *JSONTableExpression {
+	return jsonTable.add("float", keys)
+}
+
+// Bool declare boolean columns for keys
+func (jsonTable *JSONTableExpression) Bool(keys ...string) *JSONTableExpression {
+	return jsonTable.add("bool", keys)
+}
+
+// JSON declare json columns for keys, the values are kept as json, in SQL Server only objects and arrays are kept
+func (jsonTable *JSONTableExpression) JSON(keys ...string) *JSONTableExpression {
+	return jsonTable.add("json", keys)
+}
+
+func (jsonTable *JSONTableExpression) add(kind string, keys []string) *JSONTableExpression {
+	for _, key := range keys {
+		jsonTable.columns = append(jsonTable.columns, jsonTableColumn{key: key, kind: kind})
+	}
+	return jsonTable
+}
+
+// Field return the column of key, e.g. `items`.`sku`, in SQLite the value is extracted from the element
+func (jsonTable *JSONTableExpression) Field(key string) clause.Expression {
+	return jsonTableField{table: jsonTable, key: key}
+}
+
+// CrossJoin return the join of the rows to the rows of the table of the column, rows without elements are skipped
+func (jsonTable *JSONTableExpression) CrossJoin() clause.Expression {
+	return jsonTableJoin{table: jsonTable}
+}
+
+// LeftJoin return the join of the rows to the rows of the table of the column, rows without elements are
+// kept with NULL columns
+func (jsonTable *JSONTableExpression) LeftJoin() clause.Expression {
+	return jsonTableJoin{table: jsonTable, left: true}
+}
+
+// Build implements clause.Expression
+// support mysql, sqlite, postgres and sqlserver
+func (jsonTable *JSONTableExpression) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	path, err := parseJSONPath(jsonTable.path)
+	if err == nil && len(path.wildcards()) > 0 {
+		err = fmt.Errorf("invalid json path %q: wildcards are not supported by json table", jsonTable.path)
+	}
+	if err != nil {
+		_ = stmt.AddError(err)
+		return
+	}
+
+	switch stmt.Dialector.Name() {
+	case "mysql":
+		stmt.WriteString("JSON_TABLE(")
+		stmt.WriteQuoted(jsonTable.column)
+		stmt.WriteString("," + append(path[:len(path):len(path)], jsonPathSegment{kind: jsonPathAny}).literal() + " COLUMNS(")
+		for idx, column := range jsonTable.columns {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			stmt.WriteQuoted(column.key)
+			stmt.WriteString(" " + map[string]string{
+				"text": "LONGTEXT", "int": "BIGINT", "float": "DOUBLE", "bool": "BOOLEAN", "json": "JSON",
+			}[column.kind])
+			stmt.WriteString(" PATH " + jsonPath{{kind: jsonPathKey, key: column.key}}.literal())
+		}
+		stmt.WriteString(")) AS ")
+		stmt.WriteQuoted(jsonTable.alias)
+
+	case "sqlite":
+		// elements of anything but arrays are skipped
+		stmt.WriteString("json_each(CASE WHEN json_type(")
+		stmt.WriteQuoted(jsonTable.column)
+		stmt.WriteByte(',')
+		stmt.AddVar(stmt, path.sql("sqlite"))
+		stmt.WriteString(") = 'array' THEN ")
+		stmt.WriteQuoted(jsonTable.column)
+		stmt.WriteString(" END,")
+		stmt.AddVar(stmt, path.sql("sqlite"))
+		stmt.WriteString(") AS ")
+		stmt.WriteQuoted(jsonTable.alias)
+
+	case "postgres":
+		stmt.WriteString("jsonb_to_recordset(CASE WHEN jsonb_typeof(")
+		writeJSONBPath(stmt, jsonSource{column: jsonTable.column}, path)
+		stmt.WriteString(") = 'array' THEN ")
+		writeJSONBPath(stmt, jsonSource{column: jsonTable.column}, path)
+		stmt.WriteString(" ELSE '[]' END) AS ")
+		stmt.WriteQuoted(jsonTable.alias)
+		stmt.WriteByte('(')
+		for idx, column := range jsonTable.columns {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			stmt.WriteQuoted(column.key)
+			stmt.WriteString(" " + map[string]string{
+				"text": "text", "int": "bigint", "float": "double precision", "bool": "boolean", "json": "jsonb",
+			}[column.kind])
+		}
+		stmt.WriteByte(')')
+
+	case "sqlserver":
+		// elements of anything but arrays are skipped, OPENJSON expands the members of objects
+		stmt.WriteString("OPENJSON(CASE WHEN LEFT(LTRIM(JSON_QUERY(")
+		stmt.WriteQuoted(jsonTable.column)
+		stmt.WriteString("," + path.literal() + ")),1) = '[' THEN ")
+		stmt.WriteQuoted(jsonTable.column)
+		stmt.WriteString(" END," + path.literal() + ") WITH (")
+		for idx, column := range jsonTable.columns {
+			if idx > 0 {
+				stmt.WriteByte(',')
+			}
+			stmt.WriteQuoted(column.key)
+			stmt.WriteString(" " + map[string]string{
+				"text": "NVARCHAR(MAX)", "int": "BIGINT", "float": "FLOAT", "bool": "BIT", "json": "NVARCHAR(MAX)",
+			}[column.kind])
+			stmt.WriteString(" " + jsonPath{{kind: jsonPathKey, key: column.key}}.literal())
+			if column.kind == "json" {
+				stmt.WriteString(" AS JSON")
+			}
+		}
+		stmt.WriteString(") AS ")
+		stmt.WriteQuoted(jsonTable.alias)
+
+	default:
+		_ = stmt.AddError(fmt.Errorf("json table is not supported by %s", stmt.Dialector.Name()))
+	}
+}
+
+type jsonTableJoin struct {
+	table *JSONTableExpression
+	left  bool
+}
+
+// Build implements clause.Expression
+func (join jsonTableJoin) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	// SQL Server has no lateral joins, other databases resolve the column of the table functions from the left
+	switch {
+	case stmt.Dialector.Name() == "sqlserver" && join.left:
+		stmt.WriteString("OUTER APPLY ")
+		join.table.Build(stmt)
+	case stmt.Dialector.Name() == "sqlserver":
+		stmt.WriteString("CROSS APPLY ")
+		join.table.Build(stmt)
+	case join.left:
+		stmt.WriteString("LEFT JOIN ")
+		join.table.Build(stmt)
+		stmt.WriteString(" ON 1 = 1")
+	default:
+		stmt.WriteString("CROSS JOIN ")
+		join.table.Build(stmt)
+	}
+}
+
+type jsonTableField struct {
+	table *JSONTableExpression
+	key   string
+}
+
+// Build implements clause.Expression
+func (field jsonTableField) Build(builder clause.Builder) {
+	stmt, ok := builder.(*gorm.Statement)
+	if !ok {
+		return
+	}
+
+	var column *jsonTableColumn
+	for idx := range field.table.columns {
+		if field.table.columns[idx].key == field.key {
+			column = &field.table.columns[idx]
+		}
+	}
+	if column == nil {
+		_ = stmt.AddError(fmt.Errorf("unknown column %q of json table %s", field.key, field.table.alias))
+		return
+	}
+
+	if stmt.Dialector.Name() != "sqlite" {
+		stmt.WriteQuoted(field.table.alias)
+		stmt.WriteByte('.')
+		stmt.WriteQuoted(column.key)
+		return
+	}
+
+	src, path := jsonSource{column: field.table.alias + ".value"}, jsonPath{{kind: jsonPathKey, key: column.key}}
+	if column.kind == "json" {
+		stmt.WriteString("CASE WHEN ")
+		writeJSONExtract(stmt, "json_type", src, path)
+		stmt.WriteString(" IS NOT NULL THEN json_quote(")
+		writeJSONExtract(stmt, "json_extract", src, path)
+		stmt.WriteString(") END")
+		return
+	}
+	(&JSONExtractExpression{kind: column.kind}).writeValue(stmt, src, path)
+}
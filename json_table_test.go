@@ -0,0 +1,112 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONTable(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type LineItem struct {
+			SKU   string   `json:"sku"`
+			Qty   int      `json:"qty"`
+			Price float64  `json:"price"`
+			Gift  bool     `json:"gift"`
+			Tags  []string `json:"tags,omitempty"`
+		}
+
+		type Order struct {
+			gorm.Model
+			Name  string
+			Items datatypes.JSONSlice[LineItem]
+			Attrs datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&Order{})
+		if err := DB.Migrator().AutoMigrate(&Order{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		orders := []Order{{
+			Name:  "order-1",
+			Items: datatypes.JSONSlice[LineItem]{{SKU: "sku-1", Qty: 2, Price: 1.5}, {SKU: "sku-2", Qty: 1, Price: 10, Gift: true, Tags: []string{"red"}}},
+			Attrs: datatypes.JSON(`{"lines": [{"sku": "sku-3", "qty": 4}]}`),
+		}, {
+			Name:  "order-2",
+			Items: datatypes.JSONSlice[LineItem]{{SKU: "sku-1", Qty: 5, Price: 1.5}},
+			Attrs: datatypes.JSON(`{"lines": {"sku": "sku-4"}}`),
+		}, {
+			Name:  "order-3",
+			Items: datatypes.JSONSlice[LineItem]{},
+			Attrs: datatypes.JSON(`{}`),
+		}}
+
+		if err := DB.Create(&orders).Error; err != nil {
+			t.Errorf("Failed to create orders %v", err)
+		}
+
+		type Line struct {
+			Name  string
+			SKU   string
+			Qty   int
+			Price float64
+			Gift  bool
+		}
+
+		items := datatypes.JSONTable("orders.items", "items").Text("sku").Int("qty").Float("price").Bool("gift").JSON("tags")
+		var lines []Line
+		if err := DB.Table("orders").Joins("?", items.CrossJoin()).
+			Select("orders.name, ? AS sku, ? AS qty, ? AS price, ? AS gift", items.Field("sku"), items.Field("qty"), items.Field("price"), items.Field("gift")).
+			Order("orders.id").Order("sku").Scan(&lines).Error; err != nil {
+			t.Fatalf("failed to expand json array, got error %v", err)
+		}
+		AssertEqual(t, lines, []Line{
+			{Name: "order-1", SKU: "sku-1", Qty: 2, Price: 1.5},
+			{Name: "order-1", SKU: "sku-2", Qty: 1, Price: 10, Gift: true},
+			{Name: "order-2", SKU: "sku-1", Qty: 5, Price: 1.5},
+		})
+
+		type Total struct {
+			SKU string
+			Qty int
+		}
+
+		// SQL Server can't group by aliases
+		group := "sku"
+		if DB.Dialector.Name() == "sqlserver" {
+			group = "items.sku"
+		}
+
+		var totals []Total
+		if err := DB.Table("orders").Joins("?", items.CrossJoin()).
+			Select("? AS sku, SUM(?) AS qty", items.Field("sku"), items.Field("qty")).
+			Where("? > ?", items.Field("price"), 1).Group(group).Order("sku").Scan(&totals).Error; err != nil {
+			t.Fatalf("failed to aggregate json array, got error %v", err)
+		}
+		AssertEqual(t, totals, []Total{{SKU: "sku-1", Qty: 7}, {SKU: "sku-2", Qty: 1}})
+
+		var names []string
+		if err := DB.Table("orders").Joins("?", items.LeftJoin()).
+			Where("? IS NULL", items.Field("sku")).Order("orders.id").Pluck("orders.name", &names).Error; err != nil {
+			t.Fatalf("failed to left join json array, got error %v", err)
+		}
+		AssertEqual(t, names, []string{"order-3"})
+
+		var tags []string
+		if err := DB.Table("orders").Joins("?", items.CrossJoin()).
+			Where("? IS NOT NULL", items.Field("tags")).Select("?", items.Field("tags")).Scan(&tags).Error; err != nil {
+			t.Fatalf("failed to find json column, got error %v", err)
+		}
+		AssertEqual(t, len(tags), 1)
+
+		var skus []string
+		nested := datatypes.JSONTable("orders.attrs", "lines").Path("lines").Text("sku")
+		if err := DB.Table("orders").Joins("?", nested.CrossJoin()).Select("?", nested.Field("sku")).Order("orders.id").Scan(&skus).Error; err != nil {
+			t.Fatalf("failed to expand nested json array, got error %v", err)
+		}
+		AssertEqual(t, skus, []string{"sku-3"})
+	}
+}
@@ -3,19 +3,22 @@ package datatypes_test
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"gorm.io/datatypes"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
 var _ driver.Valuer = &datatypes.JSON{}
 
 func TestJSON(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql", "postgres") {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
 		type UserWithJSON struct {
 			gorm.Model
 			Name       string
@@ -118,29 +121,785 @@ func TestJSON(t *testing.T) {
 			t.Fatalf("failed to find user with json value, got error %v", err)
 		}
 
-		var results9 []UserWithJSON
-		if err := DB.Where("? = ?", datatypes.JSONQuery("attributes").Extract("name"), "json-2").Find(&results9).Error; err != nil || len(results9) != 1 {
-			t.Fatalf("failed to find user with json value, got error %v", err)
+		var results9 []UserWithJSON
+		if err := DB.Where("? = ?", datatypes.JSONQuery("attributes").Extract("name"), "json-2").Find(&results9).Error; err != nil || len(results9) != 1 {
+			t.Fatalf("failed to find user with json value, got error %v", err)
+		}
+		AssertEqual(t, results9[0].Name, users[1].Name)
+
+		var result10 UserWithJSON
+		if err := DB.First(&result10, datatypes.JSONQuery("attributes").Likes("%dmi%", "role")).Error; err != nil {
+			t.Fatalf("failed to find user with json value, got error %v", err)
+		}
+		AssertEqual(t, result10.Name, users[1].Name)
+	}
+}
+
+func TestJSONOverlaps(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`["tag1", "tag2", 3]`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`["tag3", "3", null]`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`"tag1"`),
+		}, {
+			Name:       "json-4",
+			Attributes: datatypes.JSON(`{"role": "admin", "age": 18}`),
+		}, {
+			Name: "json-5",
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			value  string
+			expect []string
+		}{
+			{name: "strings", value: `["tag1", "tag4"]`, expect: []string{"json-1", "json-3"}},
+			{name: "types", value: `[3]`, expect: []string{"json-1"}},
+			{name: "null", value: `[null, "tag5"]`, expect: []string{"json-2"}},
+			{name: "scalar", value: `"tag3"`, expect: []string{"json-2"}},
+			{name: "object members", value: `{"age": 18, "role": "guest"}`, expect: []string{"json-4"}},
+			{name: "object element", value: `[{"role": "admin", "age": 18}]`, expect: []string{"json-4"}},
+			{name: "none", value: `["tag5"]`, expect: []string{}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(datatypes.JSONOverlaps(datatypes.Column("attributes"), test.value)).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json overlaps, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONQueryCompare(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"age": 8, "role": "guest", "score": 1.5, "joined": "2020-01-01T10:00:00Z"}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"age": 28, "role": "admin", "score": 7.25, "joined": "2021-06-01T10:00:00Z"}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"age": 100, "role": "tester", "score": 9, "joined": "2023-03-01T10:00:00Z"}`),
+		}, {
+			Name:       "json-4",
+			Attributes: datatypes.JSON(`{"age": "unknown", "role": "admin", "score": "9.5"}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{"gt", datatypes.JSONQuery("attributes").Gt(10, "age"), []string{"json-2", "json-3"}},
+			{"gte", datatypes.JSONQuery("attributes").Gte(28, "age"), []string{"json-2", "json-3"}},
+			{"lt", datatypes.JSONQuery("attributes").Lt(28, "age"), []string{"json-1"}},
+			{"lte float", datatypes.JSONQuery("attributes").Lte(7.25, "score"), []string{"json-1", "json-2"}},
+			{"number text", datatypes.JSONQuery("attributes").Gt(5, "score"), []string{"json-2", "json-3"}},
+			{"between", datatypes.JSONQuery("attributes").Between(8, 28, "age"), []string{"json-1", "json-2"}},
+			{"not equals", datatypes.JSONQuery("attributes").NotEquals("admin", "role"), []string{"json-1", "json-3"}},
+			{"in", datatypes.JSONQuery("attributes").In([]string{"guest", "tester"}, "role"), []string{"json-1", "json-3"}},
+			{"in numbers", datatypes.JSONQuery("attributes").In([]int{8, 100}, "age"), []string{"json-1", "json-3"}},
+			{"string compare", datatypes.JSONQuery("attributes").Gt("guest", "role"), []string{"json-3"}},
+			{"time", datatypes.JSONQuery("attributes").Gte(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), "joined"), []string{"json-2", "json-3"}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONQueryGroup(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"age": 18, "role": "guest", "orgs": {"orga": "orga"}}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"age": 28, "role": "admin", "orgs": {"orgb": "orgb"}}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"age": 38, "role": "admin", "banned": true}`),
+		}, {
+			Name:       "json-4",
+			Attributes: datatypes.JSON(`{"age": 48, "role": "tester"}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{
+				name:   "conditions on the same expression are joined by and",
+				query:  datatypes.JSONQuery("attributes").HasKey("orgs").Equals("admin", "role"),
+				expect: []string{"json-2"},
+			}, {
+				name: "or",
+				query: datatypes.JSONQuery("attributes").Or(
+					datatypes.JSONQuery("attributes").Equals("guest", "role"),
+					datatypes.JSONQuery("attributes").Gt(40, "age"),
+				),
+				expect: []string{"json-1", "json-4"},
+			}, {
+				name:   "not",
+				query:  datatypes.JSONQuery("attributes").Equals("admin", "role").Not(datatypes.JSONQuery("attributes").HasKey("banned")),
+				expect: []string{"json-2"},
+			}, {
+				name: "nested",
+				query: datatypes.JSONQuery("attributes").Or(
+					datatypes.JSONQuery("").HasKey("orgs", "orga"),
+					datatypes.JSONQuery("").And(
+						datatypes.JSONQuery("").Gte(28, "age"),
+						datatypes.JSONQuery("").Lt(40, "age"),
+					).Not(datatypes.JSONQuery("").Equals("admin", "role")),
+				),
+				expect: []string{"json-1"},
+			}, {
+				name: "empty group",
+				query: datatypes.JSONQuery("attributes").Equals("tester", "role").Or(
+					datatypes.JSONQuery("attributes"),
+				),
+				expect: []string{"json-4"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONQueryPath(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"tags": ["tag1", "tag2"], "items": [{"sku": "sku-1", "qty": 1}, {"sku": "sku-2", "qty": 5}], "first name": "jinzhu"}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"tags": ["tag2", "tag3"], "items": [{"sku": "sku-3", "qty": 2}]}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"tags": [], "items": []}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{
+				name:   "index",
+				query:  datatypes.JSONQuery("attributes").Equals("tag2", "tags", datatypes.JSONIndex(0)),
+				expect: []string{"json-2"},
+			}, {
+				name:   "index suffix",
+				query:  datatypes.JSONQuery("attributes").Equals("tag2", "tags[1]"),
+				expect: []string{"json-1"},
+			}, {
+				name:   "has index",
+				query:  datatypes.JSONQuery("attributes").HasKey("tags", datatypes.JSONIndex(0)),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "last",
+				query:  datatypes.JSONQuery("attributes").Equals("tag3", "tags", datatypes.JSONLastIndex),
+				expect: []string{"json-2"},
+			}, {
+				name:   "any",
+				query:  datatypes.JSONQuery("attributes").Equals("sku-2", "items", datatypes.JSONAnyIndex, "sku"),
+				expect: []string{"json-1"},
+			}, {
+				name:   "any compare",
+				query:  datatypes.JSONQuery("attributes").Gte(2, "items[*]", "qty"),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "quoted key",
+				query:  datatypes.JSONQuery("attributes").Likes("jin%", "first name"),
+				expect: []string{"json-1"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+
+		var results []UserWithJSON
+		if err := DB.Where("? = ?", datatypes.JSONQuery("attributes").Extract("items[0].sku"), "sku-3").Find(&results).Error; err != nil || len(results) != 1 {
+			t.Fatalf("failed to find user with json extract by path, got error %v, results %v", err, len(results))
+		}
+		AssertEqual(t, results[0].Name, "json-2")
+	}
+}
+
+func TestJSONQueryTypedExtract(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"role": "admin", "score": 9, "ratio": 0.5, "active": true, "created": "2024-01-02T03:04:05Z"}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"role": "tester", "score": 10.7, "ratio": 1, "active": false, "created": "2023-06-01T00:00:00Z"}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"role": "admin", "score": "100", "active": "true", "created": "2025-03-04T05:06:07+02:00"}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		type Result struct {
+			Name   string
+			Role   string
+			Score  *int64
+			Ratio  *float64
+			Active *bool
+		}
+
+		var results []Result
+		if err := DB.Model(&UserWithJSON{}).Select("name, ?, ?, ?, ?",
+			datatypes.JSONQuery("attributes").AsText("role").As("role"),
+			datatypes.JSONQuery("attributes").AsInt("score").As("score"),
+			datatypes.JSONQuery("attributes").AsFloat("ratio").As("ratio"),
+			datatypes.JSONQuery("attributes").AsBool("active").As("active"),
+		).Order("id").Scan(&results).Error; err != nil {
+			t.Fatalf("failed to select typed json values, got error %v", err)
+		}
+
+		score1, score2, ratio1, ratio2, active1, active2 := int64(9), int64(10), 0.5, 1.0, true, false
+		AssertEqual(t, results, []Result{
+			{Name: "json-1", Role: "admin", Score: &score1, Ratio: &ratio1, Active: &active1},
+			{Name: "json-2", Role: "tester", Score: &score2, Ratio: &ratio2, Active: &active2},
+			{Name: "json-3", Role: "admin"},
+		})
+
+		orders := []struct {
+			name   string
+			order  *datatypes.JSONExtractExpression
+			expect []string
+		}{
+			{
+				name:   "int desc nulls last",
+				order:  datatypes.JSONQuery("attributes").AsInt("score").Desc().NullsLast(),
+				expect: []string{"json-2", "json-1", "json-3"},
+			}, {
+				name:   "float nulls first",
+				order:  datatypes.JSONQuery("attributes").AsFloat("ratio").NullsFirst(),
+				expect: []string{"json-3", "json-1", "json-2"},
+			}, {
+				name:   "time",
+				order:  datatypes.JSONQuery("attributes").AsTime("created"),
+				expect: []string{"json-2", "json-1", "json-3"},
+			},
+		}
+
+		for _, test := range orders {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Order(clause.OrderBy{Expression: test.order}).Find(&results).Error; err != nil {
+					t.Fatalf("failed to order by typed json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+
+		type Group struct {
+			Role  string
+			Total int
+		}
+		var groups []Group
+		if err := DB.Model(&UserWithJSON{}).Select("?, count(*) AS total", datatypes.JSONQuery("attributes").AsText("role").As("role")).
+			Group("role").Order("role").Scan(&groups).Error; err != nil {
+			t.Fatalf("failed to group by typed json value, got error %v", err)
+		}
+		AssertEqual(t, groups, []Group{{Role: "admin", Total: 2}, {Role: "tester", Total: 1}})
+	}
+}
+
+func TestJSONQueryPresence(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"role": "admin", "orgs": {"orga": "orga"}, "tags": ["tag1", null]}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"role": null, "orgs": {"orga": null}, "items": [{"sku": null}, {"qty": 1}]}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"orgs": {}, "items": [{"sku": "sku-1"}]}`),
+		}, {
+			Name: "json-4",
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{
+				name:   "key exists",
+				query:  datatypes.JSONQuery("attributes").KeyExists("role"),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "nested key exists",
+				query:  datatypes.JSONQuery("attributes").KeyExists("orgs", "orga"),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "index exists",
+				query:  datatypes.JSONQuery("attributes").KeyExists("tags", datatypes.JSONIndex(1)),
+				expect: []string{"json-1"},
+			}, {
+				name:   "is json null",
+				query:  datatypes.JSONQuery("attributes").IsJSONNull("role"),
+				expect: []string{"json-2"},
+			}, {
+				name:   "index is json null",
+				query:  datatypes.JSONQuery("attributes").IsJSONNull("tags[1]"),
+				expect: []string{"json-1"},
+			}, {
+				name:   "is missing",
+				query:  datatypes.JSONQuery("attributes").IsMissing("role"),
+				expect: []string{"json-3"},
+			}, {
+				name:   "nested is missing",
+				query:  datatypes.JSONQuery("attributes").IsMissing("orgs", "orga"),
+				expect: []string{"json-3"},
+			}, {
+				name:   "is sql null",
+				query:  datatypes.JSONQuery("attributes").IsSQLNull(),
+				expect: []string{"json-4"},
+			}, {
+				name:   "missing or sql null",
+				query:  datatypes.JSONQuery("attributes").Or(datatypes.JSONQuery("").IsMissing("role"), datatypes.JSONQuery("").IsSQLNull()),
+				expect: []string{"json-3", "json-4"},
+			}, {
+				name:   "any element is missing",
+				query:  datatypes.JSONQuery("attributes").IsMissing("items", datatypes.JSONAnyIndex, "sku"),
+				expect: []string{"json-2"},
+			}, {
+				name:   "any element is json null",
+				query:  datatypes.JSONQuery("attributes").IsJSONNull("items", datatypes.JSONAnyIndex, "sku"),
+				expect: []string{"json-2"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONQueryLengthTypeKeys(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"tags": ["tag1", "tag2", "tag3", "tag4"], "meta": {"price": 10, "currency": "usd", "id": 1}}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"tags": ["tag1"], "meta": {"price": "10"}}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"tags": "tag1", "meta": {"price": null}}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *gorm.DB
+			expect []string
+		}{
+			{
+				name:   "array length",
+				query:  DB.Where("? > ?", datatypes.JSONArrayQuery("attributes").Length("tags"), 3),
+				expect: []string{"json-1"},
+			}, {
+				name:   "length of non array",
+				query:  DB.Where("? IS NULL", datatypes.JSONQuery("attributes").Length("tags")),
+				expect: []string{"json-3"},
+			}, {
+				name:   "type number",
+				query:  DB.Where("? = ?", datatypes.JSONQuery("attributes").Type("meta.price"), "number"),
+				expect: []string{"json-1"},
+			}, {
+				name:   "type null",
+				query:  DB.Where("? = ?", datatypes.JSONQuery("attributes").Type("meta.price"), "null"),
+				expect: []string{"json-3"},
+			}, {
+				name:   "type of column",
+				query:  DB.Where("? = ?", datatypes.JSONQuery("attributes").Type(""), "object"),
+				expect: []string{"json-1", "json-2", "json-3"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := test.query.Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+
+		var results []struct {
+			Length int
+			Type   string
+			Keys   datatypes.JSON
+		}
+		if err := DB.Model(&UserWithJSON{}).Select("?, ?, ?",
+			datatypes.JSONQuery("attributes").Length("tags").As("length"),
+			datatypes.JSONQuery("attributes").Type("tags[0]").As("type"),
+			datatypes.JSONQuery("attributes").Keys("meta").As("keys"),
+		).Where("name = ?", "json-1").Scan(&results).Error; err != nil {
+			t.Fatalf("failed to select json length, type and keys, got error %v", err)
+		}
+
+		var keys []string
+		if len(results) != 1 || json.Unmarshal(results[0].Keys, &keys) != nil {
+			t.Fatalf("failed to select json keys, got %v", results)
+		}
+		AssertEqual(t, results[0].Length, 4)
+		AssertEqual(t, results[0].Type, "string")
+		AssertEqual(t, keys, []string{"id", "price", "currency"})
+	}
+}
+
+func TestJSONQueryContains(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"role": "admin", "age": 18, "active": true, "tags": ["tag1", "tag2"], "orgs": {"orga": "orga", "orgb": "orgb"}}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"role": "tester", "age": 28, "active": false, "tags": ["tag2", "tag3"], "orgs": {"orga": "orga"}}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"role": "admin", "tags": [], "items": [{"sku": "sku-1", "qty": 1}]}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{
+				name:   "object",
+				query:  datatypes.JSONQuery("attributes").Contains(map[string]interface{}{"role": "admin", "orgs": map[string]interface{}{"orga": "orga"}}),
+				expect: []string{"json-1"},
+			}, {
+				name:   "array subset",
+				query:  datatypes.JSONQuery("attributes").Contains([]string{"tag2"}, "tags"),
+				expect: []string{"json-1", "json-2"},
+			}, {
+				name:   "array contains scalar",
+				query:  datatypes.JSONQuery("attributes").Contains("tag3", "tags"),
+				expect: []string{"json-2"},
+			}, {
+				name:   "number and bool",
+				query:  datatypes.JSONQuery("attributes").Contains(map[string]interface{}{"age": 28, "active": false}),
+				expect: []string{"json-2"},
+			}, {
+				name:   "nested key",
+				query:  datatypes.JSONQuery("attributes").Contains("orgb", "orgs", "orgb"),
+				expect: []string{"json-1"},
+			}, {
+				name:   "array of objects",
+				query:  datatypes.JSONQuery("attributes").Contains([]map[string]interface{}{{"sku": "sku-1"}}, "items"),
+				expect: []string{"json-3"},
+			}, {
+				name:   "any key",
+				query:  datatypes.JSONQuery("attributes").HasAnyKey([]string{"items", "active"}),
+				expect: []string{"json-1", "json-2", "json-3"},
+			}, {
+				name:   "all keys",
+				query:  datatypes.JSONQuery("attributes").HasAllKeys([]string{"orga", "orgb"}, "orgs"),
+				expect: []string{"json-1"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONContains(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type Orgs struct {
+			Orga string `json:"orga"`
+			Orgb string `json:"orgb,omitempty"`
+		}
+		type Attribute struct {
+			Role   string   `json:"role"`
+			Age    int      `json:"age"`
+			Tags   []string `json:"tags"`
+			Orgs   *Orgs    `json:"orgs"`
+			Ignore string   `json:"-"`
+		}
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"role": "admin", "age": 18, "tags": ["tag1", "tag2"], "orgs": {"orga": "orga", "orgb": "orgb"}}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"role": "tester", "age": 0, "tags": ["tag2", "tag3"], "orgs": {"orga": "orga"}}`),
+		}, {
+			Name:       "json-3",
+			Attributes: datatypes.JSON(`{"role": "admin", "tags": []}`),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
 		}
-		AssertEqual(t, results9[0].Name, users[1].Name)
 
-		var result10 UserWithJSON
-		if err := DB.First(&result10, datatypes.JSONQuery("attributes").Likes("%dmi%", "role")).Error; err != nil {
-			t.Fatalf("failed to find user with json value, got error %v", err)
+		tests := []struct {
+			name    string
+			example interface{}
+			expect  []string
+		}{
+			{
+				name:    "struct ignores zero fields",
+				example: Attribute{Role: "admin", Ignore: "ignore"},
+				expect:  []string{"json-1", "json-3"},
+			}, {
+				name:    "nested struct",
+				example: &Attribute{Tags: []string{"tag2"}, Orgs: &Orgs{Orga: "orga"}},
+				expect:  []string{"json-1", "json-2"},
+			}, {
+				name:    "map keeps zero values",
+				example: datatypes.JSONMap{"age": 0},
+				expect:  []string{"json-2"},
+			}, {
+				name:    "json type",
+				example: datatypes.NewJSONType(Attribute{Role: "admin", Orgs: &Orgs{Orgb: "orgb"}}),
+				expect:  []string{"json-1"},
+			},
 		}
-		AssertEqual(t, result10.Name, users[1].Name)
 
-		// not support for sqlite
-		// JSONOverlaps
-		//var result9 UserWithJSON
-		//if err := DB.First(&result9, datatypes.JSONOverlaps("attributes", `["tag1","tag2"]`)).Error; err != nil {
-		//	t.Fatalf("failed to find user with json value, got error %v", err)
-		//}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(datatypes.JSONContains("attributes", test.example)).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with json value, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
 	}
 }
 
 func TestJSONSliceScan(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql", "postgres") {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
 		type Param struct {
 			ID          int
 			DisplayName string
@@ -319,7 +1078,7 @@ func TestPostgresJSONSet(t *testing.T) {
 }
 
 func TestJSONSet(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql") {
+	if SupportedDriver("sqlite", "mysql", "sqlserver") {
 		type UserWithJSON struct {
 			gorm.Model
 			Name       string
@@ -435,6 +1194,10 @@ func TestJSONSet(t *testing.T) {
 				if err := DB.Model(&UserWithJSON{}).Where("name = ?", "json-4").UpdateColumn("attributes", datatypes.JSONSet("attributes").Set("extra", gorm.Expr("JSON(?)", `["a", "b"]`))).Error; err != nil {
 					t.Fatalf("failed to update user with json key, got error %v", err)
 				}
+			} else if DB.Dialector.Name() == "sqlserver" {
+				if err := DB.Model(&UserWithJSON{}).Where("name = ?", "json-4").UpdateColumn("attributes", datatypes.JSONSet("attributes").Set("extra", gorm.Expr("JSON_QUERY(?)", `["a", "b"]`))).Error; err != nil {
+					t.Fatalf("failed to update user with json key, got error %v", err)
+				}
 			}
 			var result5 UserWithJSON
 			if err := DB.First(&result5, "name = ?", "json-4").Error; err != nil {
@@ -446,8 +1209,431 @@ func TestJSONSet(t *testing.T) {
 	}
 }
 
+func TestJSONSetPath(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"name": "json-1", "orgs": {"orga": "orga"}, "tags": ["tag1", "tag2"], "first name": "jinzhu"}`),
+		}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			path   string
+			value  interface{}
+			key    string
+			expect interface{}
+		}{
+			{name: "dotted", path: "orgs.orga", value: "orgv", key: "orgs", expect: map[string]interface{}{"orga": "orgv"}},
+			{name: "index", path: "tags[0]", value: "tag3", key: "tags", expect: []interface{}{"tag3", "tag2"}},
+			{name: "quoted key", path: `"first name"`, value: "jinzhu2", key: "first name", expect: "jinzhu2"},
+			{name: "mysql form", path: "$.name", value: "json-2", key: "name", expect: "json-2"},
+			{name: "postgres form", path: "{tags, 1}", value: "tag4", key: "tags", expect: []interface{}{"tag3", "tag4"}},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if err := DB.Model(&user).UpdateColumn("attributes", datatypes.JSONSet("attributes").Set(test.path, test.value)).Error; err != nil {
+					t.Fatalf("failed to update user with json path, got error %v", err)
+				}
+
+				var result UserWithJSON
+				if err := DB.First(&result, user.ID).Error; err != nil {
+					t.Fatalf("failed to find user, got error %v", err)
+				}
+				actual := make(map[string]interface{})
+				if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+					t.Fatalf("failed to unmarshal attributes, got err %v", err)
+				}
+				AssertEqual(t, actual[test.key], test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONSetOrder(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{Name: "json-1", Attributes: datatypes.JSON(`{"age": 18, "orgs": {}}`)}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		jsonSet := func() *datatypes.JSONSetExpression {
+			return datatypes.JSONSet("attributes").Set("age", 20).Set("orgs.orga", "orga").Set("orgs", map[string]string{"orgb": "orgb"}).Set("age", 21)
+		}
+
+		var sql string
+		for i := 0; i < 10; i++ {
+			stmt := DB.Session(&gorm.Session{DryRun: true}).Model(&user).UpdateColumn("attributes", jsonSet()).Statement
+			if i > 0 && stmt.SQL.String() != sql {
+				t.Fatalf("json set sql should be deterministic, got %v and %v", sql, stmt.SQL.String())
+			}
+			sql = stmt.SQL.String()
+		}
+
+		if err := DB.Model(&user).UpdateColumn("attributes", jsonSet()).Error; err != nil {
+			t.Fatalf("failed to update user with json key, got error %v", err)
+		}
+
+		var result UserWithJSON
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("failed to find user, got error %v", err)
+		}
+		actual := make(map[string]interface{})
+		if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+			t.Fatalf("failed to unmarshal attributes, got err %v", err)
+		}
+		AssertEqual(t, actual, map[string]interface{}{"age": 21, "orgs": map[string]interface{}{"orgb": "orgb"}})
+	}
+}
+
+func TestJSONRemove(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"name": "json-1", "age": 18, "orgs": {"orga": "orga", "orgb": "orgb"}}`),
+		}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		if err := DB.Model(&user).UpdateColumn("attributes", datatypes.JSONRemove("attributes").Remove("age").Remove("orgs.orga").Remove("missing")).Error; err != nil {
+			t.Fatalf("failed to remove json keys, got error %v", err)
+		}
+
+		var result UserWithJSON
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("failed to find user, got error %v", err)
+		}
+		actual := make(map[string]interface{})
+		if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+			t.Fatalf("failed to unmarshal attributes, got err %v", err)
+		}
+		AssertEqual(t, actual, map[string]interface{}{"name": "json-1", "orgs": map[string]interface{}{"orgb": "orgb"}})
+	}
+}
+
+func TestJSONInsertAndReplace(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"name": "json-1", "age": 18}`),
+		}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		tests := []struct {
+			name   string
+			expr   *datatypes.JSONSetExpression
+			expect map[string]interface{}
+		}{
+			{
+				name:   "insert",
+				expr:   datatypes.JSONInsert("attributes").Set("age", 20).Set("role", "admin"),
+				expect: map[string]interface{}{"name": "json-1", "age": 18, "role": "admin"},
+			}, {
+				name:   "replace",
+				expr:   datatypes.JSONReplace("attributes").Set("name", "json-2").Set("tags", []string{"tag1"}),
+				expect: map[string]interface{}{"name": "json-2", "age": 18, "role": "admin"},
+			}, {
+				name:   "insert same path",
+				expr:   datatypes.JSONInsert("attributes").Set("level", 1).Set("level", 2),
+				expect: map[string]interface{}{"name": "json-2", "age": 18, "role": "admin", "level": 1},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if err := DB.Model(&user).UpdateColumn("attributes", test.expr).Error; err != nil {
+					t.Fatalf("failed to update user with json key, got error %v", err)
+				}
+
+				var result UserWithJSON
+				if err := DB.First(&result, user.ID).Error; err != nil {
+					t.Fatalf("failed to find user, got error %v", err)
+				}
+				actual := make(map[string]interface{})
+				if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+					t.Fatalf("failed to unmarshal attributes, got err %v", err)
+				}
+				AssertEqual(t, actual, test.expect)
+			})
+		}
+	}
+}
+
+func TestJSONArrayUpdate(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type Event struct {
+			Kind string
+		}
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Events     datatypes.JSONSlice[Event]
+			Attributes datatypes.JSON
+			Values     datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{
+			Name:       "json-1",
+			Events:     datatypes.JSONSlice[Event]{{Kind: "created"}},
+			Attributes: datatypes.JSON(`{"tags": ["tag1", "tag2", "tag3"]}`),
+			Values:     datatypes.JSON(`[]`),
+		}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		if err := DB.Model(&user).UpdateColumn("events", datatypes.JSONArrayUpdate("events").Append("", Event{Kind: "updated"}, Event{Kind: "deleted"})).Error; err != nil {
+			t.Fatalf("failed to append to json array, got error %v", err)
+		}
+		if err := DB.Model(&user).UpdateColumn("attributes", datatypes.JSONArrayUpdate("attributes").Prepend("tags", "tag0").Remove("tags", "tag2", "tag3").Append("tags", "tag4")).Error; err != nil {
+			t.Fatalf("failed to update json array, got error %v", err)
+		}
+
+		var result UserWithJSON
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("failed to find user, got error %v", err)
+		}
+		AssertEqual(t, result.Events, datatypes.JSONSlice[Event]{{Kind: "created"}, {Kind: "updated"}, {Kind: "deleted"}})
+
+		var attributes struct {
+			Tags []string
+		}
+		if err := json.Unmarshal(result.Attributes, &attributes); err != nil {
+			t.Fatalf("failed to unmarshal attributes, got err %v", err)
+		}
+		AssertEqual(t, attributes.Tags, []string{"tag0", "tag1", "tag4"})
+
+		// every update reads the previous updates once, so the vars of chained updates grow linearly
+		update := datatypes.JSONArrayUpdate("attributes")
+		for i := 0; i < 20; i++ {
+			update.Append("tags", "tag-"+strconv.Itoa(i)).Prepend("tags", "tag-"+strconv.Itoa(i)).Remove("tags", "tag-"+strconv.Itoa(i-1))
+		}
+
+		stmt := DB.Session(&gorm.Session{DryRun: true}).Model(&user).UpdateColumn("attributes", update).Statement
+		if len(stmt.Vars) > 5*60 {
+			t.Errorf("chained updates should not repeat previous updates, got %v vars", len(stmt.Vars))
+		}
+
+		if err := DB.Model(&user).UpdateColumn("attributes", update).Error; err != nil {
+			t.Fatalf("failed to chain json array updates, got error %v", err)
+		}
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("failed to find user, got error %v", err)
+		}
+		if err := json.Unmarshal(result.Attributes, &attributes); err != nil {
+			t.Fatalf("failed to unmarshal attributes, got err %v", err)
+		}
+		AssertEqual(t, attributes.Tags, []string{"tag-19", "tag0", "tag1", "tag4", "tag-19"})
+
+		// elements are removed only if both their type and value are equal
+		tests := []struct {
+			name   string
+			values []interface{}
+			expect string
+		}{
+			{"nil", []interface{}{nil}, `[1, true, 2, "1"]`},
+			{"true", []interface{}{true}, `[1, 2, null, "1"]`},
+			{"number", []interface{}{1}, `[true, 2, null, "1"]`},
+			{"string", []interface{}{"1"}, `[1, true, 2, null]`},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if err := DB.Model(&user).UpdateColumn("values", datatypes.JSON(`[1, true, 2, null, "1"]`)).Error; err != nil {
+					t.Fatalf("failed to update json array, got error %v", err)
+				}
+				if err := DB.Model(&user).UpdateColumn("values", datatypes.JSONArrayUpdate("values").Remove("", test.values...)).Error; err != nil {
+					t.Fatalf("failed to remove from json array, got error %v", err)
+				}
+
+				var result UserWithJSON
+				if err := DB.First(&result, user.ID).Error; err != nil {
+					t.Fatalf("failed to find user, got error %v", err)
+				}
+
+				var actual, expect []interface{}
+				if err := json.Unmarshal(result.Values, &actual); err != nil {
+					t.Fatalf("failed to unmarshal values, got err %v", err)
+				}
+				if err := json.Unmarshal([]byte(test.expect), &expect); err != nil {
+					t.Fatalf("failed to unmarshal expect, got err %v", err)
+				}
+				AssertEqual(t, actual, expect)
+			})
+		}
+	}
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		user := UserWithJSON{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"name": "json-1", "age": 18, "orgs": {"orga": "orga", "orgb": "orgb"}, "tags": ["tag1"], "role": "admin"}`),
+		}
+		if err := DB.Create(&user).Error; err != nil {
+			t.Errorf("Failed to create user %v", err)
+		}
+
+		patch := datatypes.JSONMap{
+			"age":  20,
+			"orgs": map[string]interface{}{"orga": nil, "orgc": "orgc"},
+			"tags": []string{"tag2"},
+			"role": map[string]interface{}{"name": "tester", "level": nil},
+			"name": nil,
+		}
+		if err := DB.Model(&user).UpdateColumn("attributes", datatypes.JSONMergePatch("attributes", patch)).Error; err != nil {
+			t.Fatalf("failed to patch json column, got error %v", err)
+		}
+
+		var result UserWithJSON
+		if err := DB.First(&result, user.ID).Error; err != nil {
+			t.Fatalf("failed to find user, got error %v", err)
+		}
+		actual := make(map[string]interface{})
+		if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+			t.Fatalf("failed to unmarshal attributes, got err %v", err)
+		}
+		AssertEqual(t, actual, map[string]interface{}{
+			"age":  20,
+			"orgs": map[string]interface{}{"orgb": "orgb", "orgc": "orgc"},
+			"tags": []interface{}{"tag2"},
+			"role": map[string]interface{}{"name": "tester"},
+		})
+	}
+}
+
+func TestJSONIncrement(t *testing.T) {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSON
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.JSON(`{"stats": {"views": 10, "likes": 3}, "rating": 4.5, "score": 1.5}`),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.JSON(`{"rating": true, "score": "5"}`),
+		}}
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		// deltas of the same path are added together, floats are kept when adding integers, values that are
+		// not numbers are replaced like missing numbers
+		for i := 0; i < 2; i++ {
+			if err := DB.Model(&UserWithJSON{}).Where("1 = 1").UpdateColumn("attributes", datatypes.JSONIncrement("attributes").Add("stats.views", 1).Add("stats.likes", -1).Add("rating", 0.25).Add("stats.views", 2).Add("score", 1)).Error; err != nil {
+				t.Fatalf("failed to increment json numbers, got error %v", err)
+			}
+		}
+
+		expects := []string{
+			`{"stats": {"views": 16, "likes": 1}, "rating": 5, "score": 3.5}`,
+			`{"stats": {"views": 6, "likes": -2}, "rating": 0.5, "score": 2}`,
+		}
+		views := []json.Number{"16", "6"}
+		for idx, user := range users {
+			var result UserWithJSON
+			if err := DB.First(&result, user.ID).Error; err != nil {
+				t.Fatalf("failed to find user, got error %v", err)
+			}
+
+			var actual, expect map[string]interface{}
+			if err := json.Unmarshal(result.Attributes, &actual); err != nil {
+				t.Fatalf("failed to unmarshal attributes, got err %v", err)
+			}
+			_ = json.Unmarshal([]byte(expects[idx]), &expect)
+			AssertEqual(t, actual, expect)
+
+			// integers are kept as integers
+			var stats struct {
+				Stats struct {
+					Views json.Number `json:"views"`
+				} `json:"stats"`
+			}
+			if err := json.Unmarshal(result.Attributes, &stats); err != nil {
+				t.Fatalf("failed to unmarshal attributes, got err %v", err)
+			}
+			AssertEqual(t, stats.Stats.Views, views[idx])
+		}
+	}
+}
+
 func TestJSONArrayQuery(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql") {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
 		type Param struct {
 			ID          int
 			DisplayName string
@@ -469,7 +1655,7 @@ func TestJSONArrayQuery(t *testing.T) {
 		}
 		cmp3 := Param{
 			DisplayName: "JSONArray-3",
-			Config:      datatypes.JSON("{\"test\": [\"a\", \"b\"]}"),
+			Config:      datatypes.JSON("{\"test\": [\"a\", \"b\"], \"nums\": [1, 2], \"mixed\": [\"a\", 1]}"),
 		}
 		cmp4 := Param{
 			DisplayName: "JSONArray-4",
@@ -514,6 +1700,19 @@ func TestJSONArrayQuery(t *testing.T) {
 		}
 		AssertEqual(t, len(retMultiple), 1)
 
+		// elements of mixed arrays are compared with values of their type
+		for _, value := range []interface{}{"a", 1} {
+			if err := DB.Where(datatypes.JSONArrayQuery("config").Contains(value, "mixed")).Find(&retMultiple).Error; err != nil {
+				t.Fatalf("failed to find params with json value of mixed array, got error %v", err)
+			}
+			AssertEqual(t, len(retMultiple), 1)
+		}
+
+		if err := DB.Where(datatypes.JSONArrayQuery("config").Contains("1", "mixed")).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json value of mixed array, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 0)
+
 		if err := DB.Where(datatypes.JSONArrayQuery("config").In([]string{"c", "a"})).Find(&retMultiple).Error; err != nil {
 			t.Fatalf("failed to find params with json value, got error %v", err)
 		}
@@ -523,6 +1722,73 @@ func TestJSONArrayQuery(t *testing.T) {
 			t.Fatalf("failed to find params with json value and keys, got error %v", err)
 		}
 		AssertEqual(t, len(retMultiple), 1)
+
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAll([]string{"a", "b", "a"})).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json values, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 1)
+		AssertEqual(t, retMultiple[0].DisplayName, "JSONArray-1")
+
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAll([]string{"a"}, "test")).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json values and keys, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 1)
+		AssertEqual(t, retMultiple[0].DisplayName, "JSONArray-3")
+
+		// the same numbers of different types are counted once
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAll([]interface{}{1, int64(1), 1.0}, "nums")).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json numbers, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 1)
+		AssertEqual(t, retMultiple[0].DisplayName, "JSONArray-3")
+
+		// every array contains empty values, other values don't
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAll([]string{}, "test")).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with empty json values, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 1)
+		AssertEqual(t, retMultiple[0].DisplayName, "JSONArray-3")
+
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAny([]string{"b", "c"})).Order("id").Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json values, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 2)
+
+		if err := DB.Where(datatypes.JSONArrayQuery("config").ContainsAny([]string{"c"}, "test")).Find(&retMultiple).Error; err != nil {
+			t.Fatalf("failed to find params with json values and keys, got error %v", err)
+		}
+		AssertEqual(t, len(retMultiple), 0)
+
+		// MySQL 8.0.17+ queries with MEMBER OF, JSON_CONTAINS and JSON_OVERLAPS on the path, which can use
+		// multi-valued indexes
+		memberOfTests := []struct {
+			name   string
+			query  *datatypes.JSONArrayExpression
+			sql    string
+			expect []string
+		}{
+			{"contains", datatypes.JSONArrayQuery("config").Contains("a", "test"), "MEMBER OF(`config`->'$.test')", []string{"JSONArray-3"}},
+			{"contains all", datatypes.JSONArrayQuery("config").ContainsAll([]string{"b", "a"}, "test"), "JSON_CONTAINS(`config`->'$.test'", []string{"JSONArray-3"}},
+			{"contains all of column", datatypes.JSONArrayQuery("config").ContainsAll([]string{"a", "c"}), "JSON_CONTAINS(`config`->'$'", []string{"JSONArray-2"}},
+			{"contains any", datatypes.JSONArrayQuery("config").ContainsAny([]string{"d", "b"}, "test"), "JSON_OVERLAPS(`config`->'$.test'", []string{"JSONArray-3"}},
+			{"contains any of column", datatypes.JSONArrayQuery("config").ContainsAny([]string{"b", "c"}), "JSON_OVERLAPS(`config`->'$'", []string{"JSONArray-1", "JSONArray-2"}},
+		}
+		for _, test := range memberOfTests {
+			t.Run(test.name, func(t *testing.T) {
+				if DB.Dialector.Name() == "mysql" {
+					sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+						return tx.Where(test.query).Find(&[]Param{})
+					})
+					AssertEqual(t, strings.Contains(sql, test.sql), datatypes.SupportsMySQLMemberOf(DB))
+				}
+
+				var names []string
+				if err := DB.Model(&Param{}).Where(test.query).Order("id").Pluck("display_name", &names).Error; err != nil {
+					t.Fatalf("failed to find params with json values, got error %v", err)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
 	}
 }
 
@@ -77,6 +77,8 @@ func (JSONType[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
 	}
 	return ""
 }
@@ -138,6 +140,8 @@ func (JSONSlice[T]) GormDBDataType(db *gorm.DB, field *schema.Field) string {
 		return "JSON"
 	case "postgres":
 		return "JSONB"
+	case "sqlserver":
+		return "NVARCHAR(MAX)"
 	}
 	return ""
 }
@@ -18,7 +18,7 @@ func newJSONType[T any](b []byte) datatypes.JSONType[T] {
 }
 
 func TestJSONType(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql", "postgres") {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
 		type Attribute struct {
 			Sex   int
 			Age   int
@@ -131,7 +131,7 @@ func TestJSONType(t *testing.T) {
 }
 
 func TestJSONSlice(t *testing.T) {
-	if SupportedDriver("sqlite", "mysql", "postgres") {
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
 		type Tag struct {
 			Name  string
 			Score float64
@@ -0,0 +1,242 @@
+package datatypes
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JSONTypedPath json path resolved from the fields of T, implements the conditions of JSONQueryExpression with
+// values checked against the type of the field, see JSONPathOf
+type JSONTypedPath[T any] struct {
+	keys []string
+	typ  reflect.Type
+	err  error
+}
+
+// JSONPathOf resolve the json path of the field whose address is returned by field, keys are the names of
+// the json tags of the fields, embedded structs are flattened like encoding/json
+//
+//	type Attributes struct {
+//		Orgs struct {
+//			Orga string `json:"orga"`
+//		} `json:"orgs"`
+//	}
+//
+//	orga := JSONPathOf[Attributes](func(t *Attributes) any { return &t.Orgs.Orga })
+//	DB.Where(orga.Equals("attributes", "orgv")) // the same as JSONQuery("attributes").Equals("orgv", "orgs", "orga")
+//	DB.Where(orga.Equals("attributes", 42))     // error: invalid value 42 of type int for json path orgs.orga of type string
+func JSONPathOf[T any](field func(t *T) any) JSONTypedPath[T] {
+	root := reflect.New(reflect.TypeOf((*T)(nil)).Elem())
+	if root.Elem().Kind() != reflect.Struct {
+		return JSONTypedPath[T]{err: fmt.Errorf("invalid json path of %s, should be a struct", root.Elem().Type())}
+	}
+	allocJSONPathFields(root.Elem(), map[reflect.Type]bool{})
+
+	target := reflect.ValueOf(field(root.Interface().(*T)))
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return JSONTypedPath[T]{err: fmt.Errorf("invalid json path of %s, should return the address of a field", root.Elem().Type())}
+	}
+
+	keys, ok := findJSONPathField(root.Elem(), target, nil)
+	if !ok {
+		return JSONTypedPath[T]{err: fmt.Errorf("invalid json path of %s, %s is not a json field", root.Elem().Type(), target.Type().Elem())}
+	}
+	return JSONTypedPath[T]{keys: keys, typ: target.Type().Elem()}
+}
+
+// allocJSONPathFields allocates nil pointers to structs, so field can select fields of nested pointers
+func allocJSONPathFields(v reflect.Value, parents map[reflect.Type]bool) {
+	if parents[v.Type()] {
+		return
+	}
+	parents[v.Type()] = true
+	defer delete(parents, v.Type())
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct && !parents[field.Type().Elem()] {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct {
+			allocJSONPathFields(field, parents)
+		}
+	}
+}
+
+// findJSONPathField finds the keys of the field of v at the address of target
+func findJSONPathField(v reflect.Value, target reflect.Value, keys []string) ([]string, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		structField, field := v.Type().Field(i), v.Field(i)
+		if !structField.IsExported() && !structField.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldKeys := keys
+		if name != "" || !structField.Anonymous {
+			if name == "" {
+				name = structField.Name
+			}
+			fieldKeys = append(keys[:len(keys):len(keys)], name)
+		}
+
+		if field.Type() == target.Type().Elem() && field.Addr().Pointer() == target.Pointer() {
+			return fieldKeys, true
+		}
+
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct {
+			if found, ok := findJSONPathField(field, target, fieldKeys); ok {
+				return found, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Keys return the keys of the path, which can be used with JSONQuery
+func (path JSONTypedPath[T]) Keys() []string {
+	return path.keys
+}
+
+// String return the path like `orgs.orga`, which can be used with JSONQuery("attributes").Extract and JSONSet
+func (path JSONTypedPath[T]) String() string {
+	return JSONKeysPath(path.keys...)
+}
+
+// Err return the error of resolving the path
+func (path JSONTypedPath[T]) Err() error {
+	return path.err
+}
+
+// HasKey checks if the path exists in column
+func (path JSONTypedPath[T]) HasKey(column string) *JSONQueryExpression {
+	return path.query(column).HasKey(path.keys...)
+}
+
+// Equals checks if the value of the path in column equals value
+func (path JSONTypedPath[T]) Equals(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).Equals(value, path.keys...)
+}
+
+// NotEquals checks if the value of the path in column is not equal to value
+func (path JSONTypedPath[T]) NotEquals(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).NotEquals(value, path.keys...)
+}
+
+// Gt checks if the value of the path in column is greater than value
+func (path JSONTypedPath[T]) Gt(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).Gt(value, path.keys...)
+}
+
+// Gte checks if the value of the path in column is greater than or equal to value
+func (path JSONTypedPath[T]) Gte(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).Gte(value, path.keys...)
+}
+
+// Lt checks if the value of the path in column is less than value
+func (path JSONTypedPath[T]) Lt(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).Lt(value, path.keys...)
+}
+
+// Lte checks if the value of the path in column is less than or equal to value
+func (path JSONTypedPath[T]) Lte(column string, value interface{}) *JSONQueryExpression {
+	return path.query(column, value).Lte(value, path.keys...)
+}
+
+// Between checks if the value of the path in column is between lower and upper, inclusive
+func (path JSONTypedPath[T]) Between(column string, lower, upper interface{}) *JSONQueryExpression {
+	return path.query(column, lower, upper).Between(lower, upper, path.keys...)
+}
+
+// In checks if the value of the path in column is one of the elements of the slice values
+func (path JSONTypedPath[T]) In(column string, values interface{}) *JSONQueryExpression {
+	var elems []interface{}
+	if rv := reflect.ValueOf(values); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, rv.Index(i).Interface())
+		}
+	} else {
+		elems = append(elems, values)
+	}
+	return path.query(column, elems...).In(values, path.keys...)
+}
+
+// Likes checks if the value of the path in column matches the pattern value, the field should be a string
+func (path JSONTypedPath[T]) Likes(column string, value string) *JSONQueryExpression {
+	return path.query(column, value).Likes(value, path.keys...)
+}
+
+// Contains checks if the json of the path in column contains value like JSONQueryExpression.Contains,
+// value can be an element of slices
+func (path JSONTypedPath[T]) Contains(column string, value interface{}) *JSONQueryExpression {
+	typ := path.typ
+	if typ != nil && typ.Kind() == reflect.Slice && !isJSONPathValueOf(typ, value) {
+		typ = typ.Elem()
+	}
+	return path.queryOf(column, typ, value).Contains(value, path.keys...)
+}
+
+// query returns the query of column, with the error of the path or of values that can't be compared with the field
+func (path JSONTypedPath[T]) query(column string, values ...interface{}) *JSONQueryExpression {
+	return path.queryOf(column, path.typ, values...)
+}
+
+func (path JSONTypedPath[T]) queryOf(column string, typ reflect.Type, values ...interface{}) *JSONQueryExpression {
+	query := JSONQuery(column)
+	query.err = path.err
+	for _, value := range values {
+		if query.err == nil && !isJSONPathValueOf(typ, value) {
+			query.err = fmt.Errorf("invalid value %v of type %T for json path %s of type %s", value, value, path, typ)
+		}
+	}
+	return query
+}
+
+// isJSONPathValueOf checks if value can be compared with the json of fields of typ, numbers are compared
+// with numbers, strings with strings, others should be assignable to typ
+func isJSONPathValueOf(typ reflect.Type, value interface{}) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if value == nil {
+		return true
+	}
+	valueType := reflect.TypeOf(value)
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Interface, valueType.AssignableTo(typ):
+		return true
+	case isJSONPathNumber(typ.Kind()):
+		return isJSONPathNumber(valueType.Kind())
+	case typ.Kind() == reflect.String, typ.Kind() == reflect.Bool:
+		return valueType.Kind() == typ.Kind()
+	}
+	return false
+}
+
+func isJSONPathNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
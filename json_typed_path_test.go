@@ -0,0 +1,128 @@
+package datatypes_test
+
+import (
+	"testing"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestJSONPathOf(t *testing.T) {
+	type Org struct {
+		Orga string `json:"orga"`
+	}
+
+	type Base struct {
+		Role string `json:"role"`
+	}
+
+	type Attributes struct {
+		Base
+		Name  string   `json:"name"`
+		Age   int      `json:"age,omitempty"`
+		Tags  []string `json:"tags"`
+		Orgs  Org      `json:"orgs"`
+		Owner *Org     `json:"owner"`
+		Note  string   `json:"-"`
+		Email string
+	}
+
+	paths := []struct {
+		path datatypes.JSONTypedPath[Attributes]
+		keys []string
+	}{
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Name }), []string{"name"}},
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Orgs.Orga }), []string{"orgs", "orga"}},
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Owner.Orga }), []string{"owner", "orga"}},
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Orgs }), []string{"orgs"}},
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Role }), []string{"role"}},
+		{datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Email }), []string{"Email"}},
+	}
+	for _, path := range paths {
+		if err := path.path.Err(); err != nil {
+			t.Errorf("failed to resolve json path %v, got error %v", path.keys, err)
+		}
+		AssertEqual(t, path.path.Keys(), path.keys)
+	}
+	AssertEqual(t, datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Orgs.Orga }).String(), "orgs.orga")
+
+	if err := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Note }).Err(); err == nil {
+		t.Errorf("should fail to resolve json path of ignored field")
+	}
+	if err := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return t.Name }).Err(); err == nil {
+		t.Errorf("should fail to resolve json path of value")
+	}
+
+	if SupportedDriver("sqlite", "mysql", "postgres", "sqlserver") {
+		type UserWithJSON struct {
+			gorm.Model
+			Name       string
+			Attributes datatypes.JSONType[Attributes]
+		}
+
+		DB.Migrator().DropTable(&UserWithJSON{})
+		if err := DB.Migrator().AutoMigrate(&UserWithJSON{}); err != nil {
+			t.Errorf("failed to migrate, got error: %v", err)
+		}
+
+		users := []UserWithJSON{{
+			Name:       "json-1",
+			Attributes: datatypes.NewJSONType(Attributes{Name: "json-1", Age: 18, Tags: []string{"tag1"}, Orgs: Org{Orga: "orga"}}),
+		}, {
+			Name:       "json-2",
+			Attributes: datatypes.NewJSONType(Attributes{Base: Base{Role: "admin"}, Name: "json-2", Age: 28, Tags: []string{"tag2"}}),
+		}}
+
+		if err := DB.Create(&users).Error; err != nil {
+			t.Errorf("Failed to create users %v", err)
+		}
+
+		orga := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Orgs.Orga })
+		age := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Age })
+		role := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Role })
+		tests := []struct {
+			name   string
+			query  *datatypes.JSONQueryExpression
+			expect []string
+		}{
+			{name: "equals", query: orga.Equals("attributes", "orga"), expect: []string{"json-1"}},
+			{name: "gt", query: age.Gt("attributes", int8(20)), expect: []string{"json-2"}},
+			{name: "between", query: age.Between("attributes", 10, 20.5), expect: []string{"json-1"}},
+			{name: "in", query: role.In("attributes", []string{"admin", "guest"}), expect: []string{"json-2"}},
+			{name: "or", query: datatypes.JSONQuery("attributes").Or(orga.Equals("", "orga"), role.Equals("", "admin")), expect: []string{"json-1", "json-2"}},
+		}
+
+		if SupportedDriver("sqlite", "mysql", "postgres") {
+			tags := datatypes.JSONPathOf[Attributes](func(t *Attributes) any { return &t.Tags })
+			tests = append(tests, struct {
+				name   string
+				query  *datatypes.JSONQueryExpression
+				expect []string
+			}{name: "contains", query: tags.Contains("attributes", "tag2"), expect: []string{"json-2"}})
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var results []UserWithJSON
+				if err := DB.Where(test.query).Order("id").Find(&results).Error; err != nil {
+					t.Fatalf("failed to find users with typed json path, got error %v", err)
+				}
+
+				names := make([]string, 0, len(results))
+				for _, result := range results {
+					names = append(names, result.Name)
+				}
+				AssertEqual(t, names, test.expect)
+			})
+		}
+
+		var results []UserWithJSON
+		if err := DB.Where(orga.Equals("attributes", 42)).Find(&results).Error; err == nil {
+			t.Errorf("should fail to compare string field with int")
+		}
+		if err := DB.Where(age.In("attributes", []string{"18"})).Find(&results).Error; err == nil {
+			t.Errorf("should fail to compare int field with strings")
+		}
+	}
+}